@@ -6,23 +6,64 @@
 //
 //	go-analyzer /path/to/project
 //	go-analyzer -o output.json /path/to/project
+//	go-analyzer -watch /path/to/project
+//	go-analyzer -openapi -openapi-format=yaml /path/to/project
+//	go-analyzer -mock-package=github.com/user/repo/internal/repository -mock-out-dir=mocks /path/to/project
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
-	"github.com/fanki/go-analyzer/pkg/analysis"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/nilness"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/unusedresult"
+
+	goanalyzer "github.com/fanki/go-analyzer/pkg/analysis"
+	"github.com/fanki/go-analyzer/pkg/mockgen"
+	"github.com/fanki/go-analyzer/pkg/openapi"
 )
 
+// builtinAnalyzers are the go/analysis checks the CLI can enable by
+// name via -analyzer. Extend this set as more vet/staticcheck passes
+// are wired in. Unused-variable/import style checking lives outside
+// golang.org/x/tools (honnef.co/go/tools's "unused"), which this
+// module doesn't depend on, so unusedresult stands in as the closest
+// built-in equivalent.
+var builtinAnalyzers = []*analysis.Analyzer{
+	printf.Analyzer,
+	unusedresult.Analyzer,
+	nilness.Analyzer,
+	shadow.Analyzer,
+}
+
 func main() {
 	outputFile := flag.String("o", "", "output file path (default: stdout)")
+	analyzerNames := flag.String("analyzer", "", "comma-separated analyzer names to run (e.g. printf,unusedresult)")
+	analyzerAll := flag.Bool("analyzer-all", false, "run every built-in analyzer")
+	noCache := flag.Bool("no-cache", false, "disable the on-disk analysis cache")
+	cacheDir := flag.String("cache-dir", "", "override the on-disk cache directory (default: $XDG_CACHE_HOME/go-analyzer)")
+	watch := flag.Bool("watch", false, "keep running, emitting newline-delimited JSON events as packages change")
+	openapiOut := flag.Bool("openapi", false, "export an OpenAPI 3.1 document derived from detected HTTP handlers, instead of the structural analysis")
+	openapiFormat := flag.String("openapi-format", "json", "output format for -openapi: json or yaml")
+	openapiTitle := flag.String("openapi-title", "", "title for the generated OpenAPI document (default: the module path)")
+	openapiVersion := flag.String("openapi-version", "0.0.0", "version for the generated OpenAPI document")
+	mockPkg := flag.String("mock-package", "", "generate mocks for every interface in this package import path, instead of the structural analysis")
+	mockMode := flag.String("mock-mode", "testify", "mock style for -mock-package: testify or recorder")
+	mockOutDir := flag.String("mock-out-dir", "", "directory to write generated mock files to (default: print each file to stdout)")
 	flag.Parse()
 
 	if flag.NArg() < 1 {
-		fmt.Fprintf(os.Stderr, "Usage: go-analyzer [-o output.json] <project-root>\n")
+		fmt.Fprintf(os.Stderr, "Usage: go-analyzer [-o output.json] [-analyzer=name,...] [-analyzer-all] [-no-cache] [-cache-dir=dir] [-watch] [-openapi] [-openapi-format=json|yaml] [-mock-package=path] [-mock-mode=testify|recorder] [-mock-out-dir=dir] <project-root>\n")
 		os.Exit(1)
 	}
 
@@ -34,13 +75,43 @@ func main() {
 		os.Exit(1)
 	}
 
-	analyzer := analysis.NewAnalyzer(projectRoot)
+	selected, err := selectAnalyzers(*analyzerNames, *analyzerAll)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := []goanalyzer.Option{goanalyzer.WithAnalyzers(selected...)}
+	if *noCache {
+		opts = append(opts, goanalyzer.WithNoCache())
+	}
+	if *cacheDir != "" {
+		opts = append(opts, goanalyzer.WithCacheDir(*cacheDir))
+	}
+
+	analyzer := goanalyzer.NewAnalyzer(projectRoot, opts...)
+
+	if *watch {
+		runWatch(analyzer)
+		return
+	}
+
 	result, err := analyzer.Analyze()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ERROR: analysis failed: %v\n", err)
 		os.Exit(1)
 	}
 
+	if *openapiOut {
+		writeOpenAPI(result, *openapiTitle, *openapiVersion, *openapiFormat, *outputFile)
+		return
+	}
+
+	if *mockPkg != "" {
+		writeMocks(result, *mockPkg, *mockMode, *mockOutDir)
+		return
+	}
+
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ERROR: JSON encoding failed: %v\n", err)
@@ -57,3 +128,120 @@ func main() {
 		fmt.Println(string(data))
 	}
 }
+
+// writeOpenAPI generates an OpenAPI document from result and writes it
+// in the requested format to outputFile, or stdout when outputFile is
+// empty. title falls back to result.Module when unset.
+func writeOpenAPI(result *goanalyzer.ProjectAnalysis, title, version, format, outputFile string) {
+	if title == "" {
+		title = result.Module
+	}
+	doc := openapi.Generate(result, title, version)
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "json":
+		err = openapi.WriteJSON(&buf, doc)
+	case "yaml":
+		err = openapi.WriteYAML(&buf, doc)
+	default:
+		err = fmt.Errorf("unknown -openapi-format %q (want json or yaml)", format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: generating OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, buf.Bytes(), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: writing output file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "OpenAPI export complete: %s\n", outputFile)
+		return
+	}
+	fmt.Println(buf.String())
+}
+
+// writeMocks generates mocks for every interface declared in pkgPath
+// and either writes one file per interface under outDir, or (outDir
+// empty) prints them all to stdout separated by their file name.
+func writeMocks(result *goanalyzer.ProjectAnalysis, pkgPath, mode, outDir string) {
+	packageName := pkgPath
+	if i := strings.LastIndex(packageName, "/"); i >= 0 {
+		packageName = packageName[i+1:]
+	}
+
+	files, err := mockgen.Generate(result, pkgPath, packageName, mockgen.Mode(mode))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: generating mocks: %v\n", err)
+		os.Exit(1)
+	}
+
+	if outDir == "" {
+		for _, f := range files {
+			fmt.Printf("// --- %s ---\n%s", f.Name, f.Source)
+		}
+		return
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: creating %s: %v\n", outDir, err)
+		os.Exit(1)
+	}
+	for _, f := range files {
+		path := outDir + string(os.PathSeparator) + f.Name
+		if err := os.WriteFile(path, []byte(f.Source), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Mock generation complete: %d file(s) written to %s\n", len(files), outDir)
+}
+
+// runWatch keeps analyzer running against its project root, printing
+// one JSON event per line to stdout as packages change, until the
+// process receives an interrupt.
+func runWatch(analyzer *goanalyzer.Analyzer) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	enc := json.NewEncoder(os.Stdout)
+	err := analyzer.Watch(ctx, func(ev goanalyzer.Event) {
+		if err := enc.Encode(ev); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: encoding event: %v\n", err)
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: watch failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// selectAnalyzers resolves the -analyzer/-analyzer-all flags against
+// builtinAnalyzers.
+func selectAnalyzers(names string, all bool) ([]*analysis.Analyzer, error) {
+	if all {
+		return builtinAnalyzers, nil
+	}
+	if names == "" {
+		return nil, nil
+	}
+
+	byName := make(map[string]*analysis.Analyzer, len(builtinAnalyzers))
+	for _, an := range builtinAnalyzers {
+		byName[an.Name] = an
+	}
+
+	var selected []*analysis.Analyzer
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		an, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown analyzer %q", name)
+		}
+		selected = append(selected, an)
+	}
+	return selected, nil
+}
@@ -0,0 +1,321 @@
+package analysis
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+)
+
+// APIFeature is one exported declaration in a package's public surface,
+// rendered the way golang.org/x/tools/cmd/api renders the standard
+// library's: one line per exported type, struct field, interface
+// method, concrete method, func, const, or var.
+type APIFeature struct {
+	// Pkg is the declaring package's import path.
+	Pkg string `json:"pkg"`
+
+	// Text is everything after "pkg <Pkg>, " - e.g. "type Client struct"
+	// or "method (*Client) Do(*Request) (*Response, error)".
+	Text string `json:"text"`
+}
+
+// String renders f in the canonical "pkg P, Text" line format used by
+// WriteAPI and understood by CompareAPI.
+func (f APIFeature) String() string {
+	return fmt.Sprintf("pkg %s, %s", f.Pkg, f.Text)
+}
+
+// ExportedAPI renders every exported declaration across the project's
+// in-module packages as one APIFeature per symbol, sorted
+// deterministically by String(). Unlike PackageAnalysis (built for the
+// Java-side structural dump), it walks go/types.Package.Scope directly
+// so it can expand embedded interface methods and cover const/var
+// declarations, neither of which the struct/function extraction
+// tracks.
+func (a *Analyzer) ExportedAPI() ([]APIFeature, error) {
+	pkgs, err := a.loadWholeProject()
+	if err != nil {
+		return nil, fmt.Errorf("loading project: %w", err)
+	}
+
+	var features []APIFeature
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || !strings.HasPrefix(pkg.PkgPath, a.modulePath) {
+			continue // only this module's own API surface, not its dependencies
+		}
+		features = append(features, exportedAPIForPackage(pkg.Types)...)
+	}
+
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].String() < features[j].String()
+	})
+	return features, nil
+}
+
+// WriteAPI writes features to path, one "pkg P, text" line each, in the
+// order given (ExportedAPI already returns them sorted). The result is
+// the baseline format CompareAPI reads back.
+func WriteAPI(path string, features []APIFeature) error {
+	var b strings.Builder
+	for _, f := range features {
+		b.WriteString(f.String())
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// exportedAPIForPackage lists every exported top-level declaration in
+// tpkg, plus the exported fields/methods/interface-methods hanging off
+// each exported type.
+func exportedAPIForPackage(tpkg *types.Package) []APIFeature {
+	var features []APIFeature
+	scope := tpkg.Scope()
+	pkgPath := tpkg.Path()
+
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+
+		switch o := obj.(type) {
+		case *types.TypeName:
+			features = append(features, apiFeaturesForType(pkgPath, tpkg, o)...)
+		case *types.Func:
+			sig := o.Type().(*types.Signature)
+			features = append(features, APIFeature{Pkg: pkgPath, Text: "func " + renderSignature(o.Name(), sig, tpkg)})
+		case *types.Const:
+			features = append(features, APIFeature{
+				Pkg:  pkgPath,
+				Text: fmt.Sprintf("const %s %s", o.Name(), types.TypeString(o.Type(), types.RelativeTo(tpkg))),
+			})
+		case *types.Var:
+			features = append(features, APIFeature{
+				Pkg:  pkgPath,
+				Text: fmt.Sprintf("var %s %s", o.Name(), types.TypeString(o.Type(), types.RelativeTo(tpkg))),
+			})
+		}
+	}
+
+	return features
+}
+
+// apiFeaturesForType renders obj's own declaration line, followed by
+// one line per exported struct field or (for interfaces, via
+// Interface.Complete) embedded-and-direct method, followed by one line
+// per method explicitly declared on the named type itself.
+func apiFeaturesForType(pkgPath string, tpkg *types.Package, obj *types.TypeName) []APIFeature {
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+	underlying := named.Underlying()
+
+	kind := types.TypeString(underlying, types.RelativeTo(tpkg))
+	switch underlying.(type) {
+	case *types.Struct:
+		kind = "struct"
+	case *types.Interface:
+		kind = "interface"
+	}
+	features := []APIFeature{{Pkg: pkgPath, Text: "type " + obj.Name() + " " + kind}}
+
+	switch u := underlying.(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			f := u.Field(i)
+			if !f.Exported() {
+				continue
+			}
+			features = append(features, APIFeature{
+				Pkg:  pkgPath,
+				Text: fmt.Sprintf("type %s struct, %s %s", obj.Name(), f.Name(), types.TypeString(f.Type(), types.RelativeTo(tpkg))),
+			})
+		}
+	case *types.Interface:
+		u.Complete()
+		for i := 0; i < u.NumMethods(); i++ {
+			m := u.Method(i)
+			if !m.Exported() {
+				continue
+			}
+			sig := m.Type().(*types.Signature)
+			features = append(features, APIFeature{
+				Pkg:  pkgPath,
+				Text: fmt.Sprintf("type %s interface, %s", obj.Name(), renderSignature(m.Name(), sig, tpkg)),
+			})
+		}
+	}
+
+	for i := 0; i < named.NumMethods(); i++ {
+		fn := named.Method(i)
+		if !fn.Exported() {
+			continue
+		}
+		sig := fn.Type().(*types.Signature)
+		features = append(features, APIFeature{
+			Pkg:  pkgPath,
+			Text: fmt.Sprintf("method %s %s", receiverString(sig.Recv(), obj.Name()), renderSignature(fn.Name(), sig, tpkg)),
+		})
+	}
+
+	return features
+}
+
+// receiverString renders recv the way cmd/api does: "(T)" for a value
+// receiver, "(*T)" for a pointer one.
+func receiverString(recv *types.Var, typeName string) string {
+	if recv == nil {
+		return "(" + typeName + ")"
+	}
+	if _, ok := recv.Type().(*types.Pointer); ok {
+		return "(*" + typeName + ")"
+	}
+	return "(" + typeName + ")"
+}
+
+// renderSignature renders sig as "Name(params) results", with every
+// type name qualified relative to tpkg: types declared in tpkg print
+// unqualified, types from anywhere else print with their full import
+// path (e.g. "(*Request) (*Response, error)" for a net/http method, but
+// "(*bytes.Buffer)" for a parameter from another package).
+func renderSignature(name string, sig *types.Signature, tpkg *types.Package) string {
+	full := types.TypeString(sig, types.RelativeTo(tpkg))
+	return name + strings.TrimPrefix(full, "func")
+}
+
+// APIChange describes one difference between an API baseline and a
+// freshly computed []APIFeature, keyed on each line's symbol identity
+// (its package, kind, receiver, and name - everything up to the
+// parameter list) so a modified signature surfaces as "changed" rather
+// than an unrelated add+remove pair.
+type APIChange struct {
+	Kind string `json:"kind"` // "added", "removed", "changed"
+	Pkg  string `json:"pkg"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// CompareAPI reads a baseline written by WriteAPI (one "pkg P, text"
+// line per feature, from a previous ExportedAPI run) and diffs it
+// against new, returning every Added, Removed, or Changed line.
+// Unchanged lines are omitted. Intended for use as a semver/breakage
+// checker in CI: run ExportedAPI on HEAD, CompareAPI against the
+// baseline committed alongside the last release, and fail the build on
+// any "removed" or "changed" entry.
+func CompareAPI(oldFile string, new []APIFeature) ([]APIChange, error) {
+	data, err := os.ReadFile(oldFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", oldFile, err)
+	}
+
+	oldByKey := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		oldByKey[apiLineKey(line)] = line
+	}
+
+	newByKey := make(map[string]apiFeatureLine, len(new))
+	for _, f := range new {
+		line := f.String()
+		newByKey[apiLineKey(line)] = apiFeatureLine{pkg: f.Pkg, line: line}
+	}
+
+	var changes []APIChange
+	for key, oldLine := range oldByKey {
+		nl, ok := newByKey[key]
+		if !ok {
+			changes = append(changes, APIChange{Kind: "removed", Pkg: apiLinePkg(oldLine), Old: oldLine})
+			continue
+		}
+		if nl.line != oldLine {
+			changes = append(changes, APIChange{Kind: "changed", Pkg: nl.pkg, Old: oldLine, New: nl.line})
+		}
+	}
+	for key, nl := range newByKey {
+		if _, ok := oldByKey[key]; !ok {
+			changes = append(changes, APIChange{Kind: "added", Pkg: nl.pkg, New: nl.line})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Pkg != changes[j].Pkg {
+			return changes[i].Pkg < changes[j].Pkg
+		}
+		return changes[i].New+changes[i].Old < changes[j].New+changes[j].Old
+	})
+	return changes, nil
+}
+
+// apiFeatureLine pairs a full "pkg P, text" baseline line with the
+// package it belongs to, so CompareAPI doesn't have to re-parse it.
+type apiFeatureLine struct {
+	pkg  string
+	line string
+}
+
+// apiLineKey extracts the symbol-identity key from a full "pkg P, text"
+// line, scoped by package so identical text in two packages can't
+// collide.
+func apiLineKey(line string) string {
+	pkg, text, ok := strings.Cut(strings.TrimPrefix(line, "pkg "), ", ")
+	if !ok {
+		return line
+	}
+	return pkg + "|" + apiFeatureKey(text)
+}
+
+// apiLinePkg extracts the package path from a full "pkg P, text" line.
+func apiLinePkg(line string) string {
+	pkg, _, ok := strings.Cut(strings.TrimPrefix(line, "pkg "), ", ")
+	if !ok {
+		return ""
+	}
+	return pkg
+}
+
+// apiFeatureKey reduces text to the part identifying which symbol it
+// describes, stripping whatever varies when a signature/type changes:
+// a parameter list (for funcs, methods, and interface methods), or a
+// struct field's type, or a const/var's type.
+func apiFeatureKey(text string) string {
+	if i := paramOpenIndex(text); i >= 0 {
+		return strings.TrimSpace(text[:i])
+	}
+	if comma := strings.Index(text, ", "); comma >= 0 {
+		head := text[:comma]
+		rest := strings.Fields(text[comma+2:])
+		if len(rest) > 0 {
+			return head + ", " + rest[0]
+		}
+		return head
+	}
+	fields := strings.Fields(text)
+	if len(fields) >= 2 {
+		return fields[0] + " " + fields[1]
+	}
+	return text
+}
+
+// paramOpenIndex finds the '(' that opens a signature's parameter list
+// (as opposed to a receiver's parens, e.g. the "(*Client)" in "method
+// (*Client) Do(...)"): the first '(' preceded by an identifier
+// character rather than a space or another '('.
+func paramOpenIndex(text string) int {
+	for i := 1; i < len(text); i++ {
+		if text[i] != '(' {
+			continue
+		}
+		switch text[i-1] {
+		case ' ', '(':
+			continue
+		}
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,234 @@
+package analysis
+
+import (
+	"bufio"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BuildContext selects one GOOS/GOARCH/cgo combination to analyze a
+// package under. Files guarded by `//go:build` constraints are only
+// visible to the contexts they match, so a package with platform- or
+// cgo-specific implementations needs to be loaded once per context to
+// see all of them.
+type BuildContext struct {
+	GOOS       string
+	GOARCH     string
+	CgoEnabled bool
+}
+
+// Key returns the "GOOS_GOARCH_cgo{on,off}" string used as the
+// PackageAnalysis.Variants map key.
+func (c BuildContext) Key() string {
+	cgo := "cgooff"
+	if c.CgoEnabled {
+		cgo = "cgoon"
+	}
+	return c.GOOS + "_" + c.GOARCH + "_" + cgo
+}
+
+// defaultBuildMatrix is analyzed in full for every package whose files
+// carry build constraints, unless overridden via WithBuildMatrix;
+// unconstrained packages load only its first context (see
+// analyzePackage). It covers the platform families this tool's
+// consumers most commonly ship to, each with cgo on and off since that
+// also changes which files build (cgo-gated files, `import "C"`).
+func defaultBuildMatrix() []BuildContext {
+	var matrix []BuildContext
+	for _, plat := range [][2]string{
+		{"linux", "amd64"},
+		{"linux", "arm64"},
+		{"darwin", "arm64"},
+		{"windows", "amd64"},
+	} {
+		for _, cgo := range []bool{false, true} {
+			matrix = append(matrix, BuildContext{GOOS: plat[0], GOARCH: plat[1], CgoEnabled: cgo})
+		}
+	}
+	return matrix
+}
+
+// packageHasBuildConstraints reports whether any non-test source file
+// directly inside dir carries a //go:build or // +build constraint, a
+// _GOOS/_GOARCH file-name suffix naming a defaultBuildMatrix platform,
+// or an import "C". The
+// overwhelming majority of packages have none, in which case every
+// defaultBuildMatrix context would extract an identical PackageAnalysis
+// anyway - analyzePackage uses this to skip straight to a single
+// context instead of paying for packages.Load eight times over.
+func packageHasBuildConstraints(dir string) (bool, error) {
+	files, err := sourceFiles(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".go")
+		for _, bc := range defaultBuildMatrix() {
+			if strings.HasSuffix(name, "_"+bc.GOOS) || strings.HasSuffix(name, "_"+bc.GOARCH) {
+				return true, nil
+			}
+		}
+		if parsed, err := parser.ParseFile(token.NewFileSet(), f, nil, parser.ImportsOnly); err == nil {
+			for _, imp := range parsed.Imports {
+				if imp.Path.Value == `"C"` {
+					return true, nil
+				}
+			}
+		}
+		has, err := fileHasBuildConstraint(f)
+		if err != nil {
+			return false, err
+		}
+		if has {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fileHasBuildConstraint scans path's leading comment block - the only
+// place a build constraint is recognized - for a //go:build or
+// // +build line.
+func fileHasBuildConstraint(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if constraint.IsGoBuild(line) || constraint.IsPlusBuild(line) {
+			return true, nil
+		}
+		if !strings.HasPrefix(line, "//") {
+			// Past the leading comment block (package clause or code)
+			// with no constraint found.
+			break
+		}
+	}
+	return false, scanner.Err()
+}
+
+// PackageVariant is a per-build-context snapshot of a package's shape,
+// recorded in PackageAnalysis.Variants only when that context's file set
+// diverges from the package's canonical (first-matrix-entry) view - for
+// example a windows-only file contributing a function the other
+// platforms never build.
+type PackageVariant struct {
+	Files      []string         `json:"files"`
+	Structs    []*StructInfo    `json:"structs,omitempty"`
+	Interfaces []*InterfaceInfo `json:"interfaces,omitempty"`
+	Functions  []*FunctionInfo  `json:"functions,omitempty"`
+}
+
+// tagContexts stamps every struct, interface, function, and method in a
+// freshly-extracted, single-context PackageAnalysis with that context's
+// key, ahead of merging it into the canonical view.
+func tagContexts(pa *PackageAnalysis, key string) {
+	for _, s := range pa.Structs {
+		s.Contexts = []string{key}
+		for _, m := range s.Methods {
+			m.Contexts = []string{key}
+		}
+	}
+	for _, it := range pa.Interfaces {
+		it.Contexts = []string{key}
+	}
+	for _, f := range pa.Functions {
+		f.Contexts = []string{key}
+	}
+}
+
+// mergeVariant folds variant (a single context's extraction of the same
+// directory) into merged (the running canonical view): every struct,
+// interface, and function already present by name is tagged with the
+// new context; anything new is appended. When variant's file set
+// doesn't match merged's, the full per-context shape is also kept under
+// Variants so callers can see exactly what that context builds.
+func mergeVariant(merged, variant *PackageAnalysis, key string) {
+	tagContexts(variant, key)
+
+	if !sameFileSet(merged, variant) {
+		if merged.Variants == nil {
+			merged.Variants = make(map[string]*PackageVariant)
+		}
+		merged.Variants[key] = &PackageVariant{
+			Files:      variant.Files,
+			Structs:    variant.Structs,
+			Interfaces: variant.Interfaces,
+			Functions:  variant.Functions,
+		}
+	}
+
+	mergeStructs(merged, variant.Structs, key)
+	mergeInterfaces(merged, variant.Interfaces, key)
+	mergeFunctionList(&merged.Functions, variant.Functions, key)
+}
+
+func mergeStructs(merged *PackageAnalysis, structs []*StructInfo, key string) {
+	byName := make(map[string]*StructInfo, len(merged.Structs))
+	for _, s := range merged.Structs {
+		byName[s.Name] = s
+	}
+	for _, s := range structs {
+		existing, ok := byName[s.Name]
+		if !ok {
+			merged.Structs = append(merged.Structs, s)
+			byName[s.Name] = s
+			continue
+		}
+		addContext(&existing.Contexts, key)
+		mergeFunctionList(&existing.Methods, s.Methods, key)
+	}
+}
+
+func mergeInterfaces(merged *PackageAnalysis, interfaces []*InterfaceInfo, key string) {
+	byName := make(map[string]*InterfaceInfo, len(merged.Interfaces))
+	for _, it := range merged.Interfaces {
+		byName[it.Name] = it
+	}
+	for _, it := range interfaces {
+		if existing, ok := byName[it.Name]; ok {
+			addContext(&existing.Contexts, key)
+			continue
+		}
+		merged.Interfaces = append(merged.Interfaces, it)
+		byName[it.Name] = it
+	}
+}
+
+// mergeFunctionList folds src into *dst, matching on receiver+name so
+// methods of different structs never collide with each other.
+func mergeFunctionList(dst *[]*FunctionInfo, src []*FunctionInfo, key string) {
+	byKey := make(map[string]*FunctionInfo, len(*dst))
+	for _, f := range *dst {
+		byKey[f.Receiver+"."+f.Name] = f
+	}
+	for _, f := range src {
+		k := f.Receiver + "." + f.Name
+		if existing, ok := byKey[k]; ok {
+			addContext(&existing.Contexts, key)
+			continue
+		}
+		*dst = append(*dst, f)
+		byKey[k] = f
+	}
+}
+
+func addContext(contexts *[]string, key string) {
+	for _, c := range *contexts {
+		if c == key {
+			return
+		}
+	}
+	*contexts = append(*contexts, key)
+}
@@ -0,0 +1,95 @@
+// Package cache provides a content-addressed on-disk store for
+// serialized per-package analysis results. Each package's cache key is
+// derived from the analyzer version, the hashes of its own source
+// files, and the cache keys of its in-module dependencies, so a change
+// anywhere in a package's dependency chain invalidates every package
+// that (transitively) depends on it.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Cache is an on-disk, content-addressed blob store rooted at a single
+// directory. It is deliberately ignorant of what it stores: callers
+// serialize/deserialize their own payloads.
+type Cache struct {
+	dir string
+}
+
+// Open creates (if needed) and returns a Cache rooted at dir.
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache dir %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// DefaultDir returns $XDG_CACHE_HOME/go-analyzer, falling back to
+// os.UserCacheDir() (and finally os.TempDir()) when XDG_CACHE_HOME is
+// unset.
+func DefaultDir() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "go-analyzer")
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "go-analyzer")
+}
+
+// Key computes a content-addressed cache key from the analyzer
+// version, the set of source file hashes belonging to a package
+// (order-independent), and the cache keys of its dependencies
+// (order-independent).
+func Key(analyzerVersion string, fileHashes, depKeys []string) string {
+	sortedFiles := append([]string(nil), fileHashes...)
+	sort.Strings(sortedFiles)
+	sortedDeps := append([]string(nil), depKeys...)
+	sort.Strings(sortedDeps)
+
+	h := sha256.New()
+	fmt.Fprintln(h, analyzerVersion)
+	for _, fh := range sortedFiles {
+		fmt.Fprintln(h, fh)
+	}
+	for _, dk := range sortedDeps {
+		fmt.Fprintln(h, dk)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashFile returns a content hash for the file at path, suitable for
+// inclusion in Key's fileHashes.
+func HashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Get returns the cached blob for key, if present.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores data under key, overwriting any existing entry.
+func (c *Cache) Put(key string, data []byte) error {
+	return os.WriteFile(c.path(key), data, 0644)
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
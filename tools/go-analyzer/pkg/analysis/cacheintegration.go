@@ -0,0 +1,172 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fanki/go-analyzer/pkg/analysis/cache"
+)
+
+// analyzerVersion is mixed into every cache key. Bump it whenever a
+// change to PackageAnalysis's shape or the extraction logic would make
+// previously cached blobs stale or misleading.
+const analyzerVersion = "1"
+
+// loadPackage resolves dir to a *PackageAnalysis, reusing a cached
+// result when a.cache is enabled and the package's content-addressed
+// key (derived from its own file hashes plus depKeys) is already on
+// disk. depKeys must be the resolved cache keys of dir's in-module
+// dependencies, computed by the caller in dependency order so that a
+// change anywhere upstream invalidates everything downstream.
+//
+// Returns the loaded package, the cache key used (empty when caching
+// is disabled), and any error from analysis.
+func (a *Analyzer) loadPackage(dir string, depKeys []string) (*PackageAnalysis, string, error) {
+	if a.cache == nil {
+		pkg, err := a.analyzePackage(dir)
+		return pkg, "", err
+	}
+
+	key, err := a.cacheKey(dir, depKeys)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if data, ok := a.cache.Get(key); ok {
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err == nil && entry.Package != nil {
+			pa := entry.Package
+			restoreCallEdges(pa, entry.Calls)
+			a.exportFacts(pa)
+			return pa, key, nil
+		}
+		// Corrupt or stale-format entry: fall through and re-analyze.
+	}
+
+	pkg, err := a.analyzePackage(dir)
+	if err != nil {
+		return nil, key, err
+	}
+	if pkg != nil {
+		entry := cacheEntry{Package: pkg, Calls: callEdges(pkg)}
+		if data, err := json.Marshal(entry); err == nil {
+			if err := a.cache.Put(key, data); err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: writing cache entry for %s: %v\n", dir, err)
+			}
+		}
+	}
+	return pkg, key, nil
+}
+
+// cacheEntry is what's actually written to the on-disk cache: the
+// public PackageAnalysis plus the resolved call edges (FunctionInfo.calls)
+// propagateFacts needs to infer TransitivelyPanics/HTTPReachable across
+// packages. calls is an unexported field precisely so it stays out of
+// the tool's regular JSON output, but that means a plain
+// json.Marshal(pkg)/json.Unmarshal round trip through the cache silently
+// drops it - a cache-warm run would then look to propagateFacts exactly
+// like every cross-package call was stripped out. Keying this alongside
+// pkg rather than exporting the field keeps FunctionInfo's public shape
+// unchanged for every other consumer of Analyze's output.
+type cacheEntry struct {
+	Package *PackageAnalysis    `json:"package"`
+	Calls   map[string][]string `json:"calls,omitempty"`
+}
+
+// callEdges captures pa's resolved call edges, keyed the same
+// "pkgPath.Name" / "pkgPath.StructName.Method" way exportFacts keys its
+// own facts, so restoreCallEdges can merge them back onto a PackageAnalysis
+// freshly unmarshaled from the cache.
+func callEdges(pa *PackageAnalysis) map[string][]string {
+	edges := make(map[string][]string)
+	for _, f := range pa.Functions {
+		if len(f.calls) > 0 {
+			edges[pa.Path+"."+f.Name] = f.calls
+		}
+	}
+	for _, s := range pa.Structs {
+		for _, m := range s.Methods {
+			if len(m.calls) > 0 {
+				edges[pa.Path+"."+s.Name+"."+m.Name] = m.calls
+			}
+		}
+	}
+	return edges
+}
+
+// restoreCallEdges re-populates FunctionInfo.calls on pa, just
+// unmarshaled from the cache, from the edges callEdges captured when
+// that cache entry was written.
+func restoreCallEdges(pa *PackageAnalysis, edges map[string][]string) {
+	for _, f := range pa.Functions {
+		f.calls = edges[pa.Path+"."+f.Name]
+	}
+	for _, s := range pa.Structs {
+		for _, m := range s.Methods {
+			m.calls = edges[pa.Path+"."+s.Name+"."+m.Name]
+		}
+	}
+}
+
+// cacheKey hashes every source file in dir plus depKeys into a single
+// content-addressed key, salted with configFingerprint so that changing
+// which analyzers/detectors/build contexts are active invalidates
+// previously cached blobs even when dir's own source is untouched.
+func (a *Analyzer) cacheKey(dir string, depKeys []string) (string, error) {
+	files, err := sourceFiles(dir)
+	if err != nil {
+		return "", fmt.Errorf("listing source files in %s: %w", dir, err)
+	}
+
+	hashes := make([]string, 0, len(files))
+	for _, f := range files {
+		h, err := cache.HashFile(f)
+		if err != nil {
+			return "", fmt.Errorf("hashing %s: %w", f, err)
+		}
+		hashes = append(hashes, h)
+	}
+
+	return cache.Key(analyzerVersion+"|"+a.configFingerprint(), hashes, depKeys), nil
+}
+
+// configFingerprint summarizes every Analyzer option that changes what
+// loadPackage's cached blob actually contains - which go/analysis passes
+// ran (WithAnalyzers), which FrameworkDetectors looked for routes
+// (WithDetector), and which GOOS/GOARCH/cgo contexts were analyzed
+// (WithBuildMatrix) - so that re-running with a different set of flags
+// against unchanged source invalidates the cache instead of silently
+// replaying a blob built under the old configuration. Sorted so the
+// fingerprint is independent of option registration order.
+func (a *Analyzer) configFingerprint() string {
+	analyzerNames := make([]string, 0, len(a.analyzers))
+	for _, an := range a.analyzers {
+		analyzerNames = append(analyzerNames, an.Name)
+	}
+	sort.Strings(analyzerNames)
+
+	detectorNames := make([]string, 0, len(a.detectors))
+	for _, d := range a.detectors {
+		detectorNames = append(detectorNames, d.Name())
+	}
+	sort.Strings(detectorNames)
+
+	matrix := a.buildMatrix
+	if len(matrix) == 0 {
+		matrix = defaultBuildMatrix()
+	}
+	matrixKeys := make([]string, 0, len(matrix))
+	for _, bc := range matrix {
+		matrixKeys = append(matrixKeys, bc.Key())
+	}
+	sort.Strings(matrixKeys)
+
+	return strings.Join([]string{
+		strings.Join(analyzerNames, ","),
+		strings.Join(detectorNames, ","),
+		strings.Join(matrixKeys, ","),
+	}, "/")
+}
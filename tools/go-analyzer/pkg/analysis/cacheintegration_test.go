@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestCallEdges_RoundTrip verifies that callEdges/restoreCallEdges carry
+// FunctionInfo.calls through exactly the kind of encoding/json round
+// trip loadPackage performs against the on-disk cache. calls is
+// unexported so a plain json.Marshal/Unmarshal of a PackageAnalysis
+// drops it silently - this is what cacheEntry exists to avoid.
+func TestCallEdges_RoundTrip(t *testing.T) {
+	pa := &PackageAnalysis{
+		Path: "example.com/app/internal/svc",
+		Functions: []*FunctionInfo{
+			{Name: "Init", calls: []string{"example.com/app/internal/repo.Repo.Open"}},
+		},
+		Structs: []*StructInfo{
+			{
+				Name: "Svc",
+				Methods: []*FunctionInfo{
+					{Name: "Do", calls: []string{"example.com/app/internal/repo.Repo.Risky"}},
+					{Name: "Noop"},
+				},
+			},
+		},
+	}
+
+	edges := callEdges(pa)
+
+	// Simulate the cache round trip: a fresh PackageAnalysis carrying
+	// the same exported shape but none of the unexported calls, as
+	// encoding/json.Marshal/Unmarshal would actually produce.
+	restored := &PackageAnalysis{
+		Path: pa.Path,
+		Functions: []*FunctionInfo{
+			{Name: "Init"},
+		},
+		Structs: []*StructInfo{
+			{
+				Name: "Svc",
+				Methods: []*FunctionInfo{
+					{Name: "Do"},
+					{Name: "Noop"},
+				},
+			},
+		},
+	}
+
+	restoreCallEdges(restored, edges)
+
+	gotInit := restored.Functions[0].calls
+	if len(gotInit) != 1 || gotInit[0] != "example.com/app/internal/repo.Repo.Open" {
+		t.Errorf("Init.calls after restore = %v, want [example.com/app/internal/repo.Repo.Open]", gotInit)
+	}
+
+	gotDo := restored.Structs[0].Methods[0].calls
+	if len(gotDo) != 1 || gotDo[0] != "example.com/app/internal/repo.Repo.Risky" {
+		t.Errorf("Svc.Do.calls after restore = %v, want [example.com/app/internal/repo.Repo.Risky]", gotDo)
+	}
+
+	if got := restored.Structs[0].Methods[1].calls; len(got) != 0 {
+		t.Errorf("Svc.Noop.calls after restore = %v, want empty", got)
+	}
+}
+
+// TestLoadPackage_CacheWarmPreservesCallEdges drives loadPackage itself
+// (rather than callEdges/restoreCallEdges directly) through a real
+// cold-then-warm cycle against an on-disk cache, confirming the
+// FunctionInfo a warm hit returns still carries the calls recorded on
+// the cold result when it was cached - the exact bug a plain json.Marshal(pkg)/cache.Put
+// round trip used to lose.
+func TestLoadPackage_CacheWarmPreservesCallEdges(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", `module github.com/test/cacheapp
+
+go 1.21
+`)
+	writeFile(t, dir, "repo.go", `package cacheapp
+
+type Repo struct{}
+
+func (r *Repo) Risky() {
+	panic("boom")
+}
+`)
+
+	cacheDir := t.TempDir()
+	a := NewAnalyzer(dir, WithCacheDir(cacheDir))
+	a.modulePath = "github.com/test/cacheapp"
+
+	cold, _, err := a.loadPackage(dir, nil)
+	if err != nil {
+		t.Fatalf("cold loadPackage() failed: %v", err)
+	}
+	// Inject a synthetic call edge the way resolveFieldCalls would,
+	// directly on the cold result before it gets cached, so the warm
+	// hit below has something non-trivial to lose.
+	cold.Structs[0].Methods[0].calls = []string{"github.com/test/cacheapp.Repo.Risky"}
+	entry := cacheEntry{Package: cold, Calls: callEdges(cold)}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("marshaling cache entry failed: %v", err)
+	}
+	key, err := a.cacheKey(dir, nil)
+	if err != nil {
+		t.Fatalf("cacheKey() failed: %v", err)
+	}
+	if err := a.cache.Put(key, data); err != nil {
+		t.Fatalf("cache.Put() failed: %v", err)
+	}
+
+	warm, _, err := a.loadPackage(dir, nil)
+	if err != nil {
+		t.Fatalf("warm loadPackage() failed: %v", err)
+	}
+
+	got := warm.Structs[0].Methods[0].calls
+	if len(got) != 1 || got[0] != "github.com/test/cacheapp.Repo.Risky" {
+		t.Errorf("warm (cache-hit) Risky.calls = %v, want [github.com/test/cacheapp.Repo.Risky]", got)
+	}
+}
@@ -0,0 +1,356 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// CallNode identifies a single function or method referenced by a
+// CallEdge.
+type CallNode struct {
+	// Name is the function or method name.
+	Name string `json:"name"`
+
+	// Receiver is the receiver type for methods (e.g. "*OrderService"),
+	// empty for package-level functions.
+	Receiver string `json:"receiver,omitempty"`
+
+	// Package is the node's fully-qualified package path, empty when
+	// the node falls outside the project (stdlib, third-party, or a
+	// synthetic SSA wrapper we couldn't attribute to source).
+	Package string `json:"package,omitempty"`
+
+	// Key flattens Package/Receiver/Name into "pkg.Recv.Method" (a
+	// method), "pkg.Name" (a free function), or bare "Name" (a builtin
+	// or other node with no package), so the Java-side consumer can key
+	// cross-package invocation, dead-code, and reachability queries off
+	// a single string instead of reassembling one from three fields.
+	Key string `json:"key"`
+}
+
+// CallSite is one call made from a function's body, denormalized onto
+// FunctionInfo.Callees from the same CallGraph edge so callers can ask
+// "what does this function call" without holding the whole
+// ProjectAnalysis.CallGraph.
+type CallSite struct {
+	// Callee is the target's CallNode.Key.
+	Callee string `json:"callee"`
+
+	// Receiver is the target's receiver type (e.g. "*OrderService"),
+	// empty for free functions and builtins.
+	Receiver string `json:"receiver,omitempty"`
+
+	// File and Line locate the call site (caller's file, basename).
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// Kind is "static", "dynamic" (an interface method or func-value
+	// dispatch, so Callee is only one of possibly several targets), or
+	// "builtin" (len, append, panic, ...; see builtinFuncs). Go's own
+	// call-graph builders don't emit edges for builtin calls at all, so
+	// "builtin" is mostly theoretical here - kept so a caller filtering
+	// on Kind doesn't have to special-case an unlabeled entry.
+	Kind string `json:"kind"`
+}
+
+// CallEdge is a single call site discovered by the call-graph builder.
+type CallEdge struct {
+	Caller *CallNode `json:"caller"`
+	Callee *CallNode `json:"callee"`
+
+	// File and Line locate the call site (caller's file, basename).
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// IsDynamic indicates the call is a dynamic dispatch (through an
+	// interface method or a func value) rather than a direct call, so
+	// Callee is only one of possibly several targets.
+	IsDynamic bool `json:"isDynamic,omitempty"`
+}
+
+// CallGraph is a whole-program call graph built from SSA: CHA (a fast,
+// sound over-approximation of every call) when no entry points are
+// known, or RTA (restricted to code reachable from main and detected
+// HTTP handlers, and correspondingly more precise) otherwise.
+type CallGraph struct {
+	// Algorithm is "cha" or "rta", recording which builder produced Edges.
+	Algorithm string `json:"algorithm"`
+
+	Edges []*CallEdge `json:"edges"`
+
+	// calleesOf/callersOf back the Callees/Callers/ReachableFrom queries.
+	// They are built straight from project-local FunctionInfo pointers,
+	// so they stay usable even though Edges (CallNode) is a flattened,
+	// JSON-friendly view that has already lost that identity.
+	calleesOf map[*FunctionInfo][]*FunctionInfo
+	callersOf map[*FunctionInfo][]*FunctionInfo
+}
+
+// Callees returns the functions fn calls directly, in-project only.
+func (cg *CallGraph) Callees(fn *FunctionInfo) []*FunctionInfo {
+	if cg == nil {
+		return nil
+	}
+	return cg.calleesOf[fn]
+}
+
+// Callers returns the functions that call fn directly, in-project only.
+func (cg *CallGraph) Callers(fn *FunctionInfo) []*FunctionInfo {
+	if cg == nil {
+		return nil
+	}
+	return cg.callersOf[fn]
+}
+
+// ReachableFrom returns every in-project function transitively reachable
+// from entry (entry excluded), answering "what does this handler
+// actually touch". The inverse query - "is this function unreached by
+// anything" - is just checking Callers on every function in the project.
+func (cg *CallGraph) ReachableFrom(entry *FunctionInfo) []*FunctionInfo {
+	if cg == nil {
+		return nil
+	}
+
+	visited := map[*FunctionInfo]bool{entry: true}
+	var order []*FunctionInfo
+	queue := []*FunctionInfo{entry}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range cg.calleesOf[cur] {
+			if !visited[next] {
+				visited[next] = true
+				order = append(order, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return order
+}
+
+// buildCallGraph constructs a whole-program CallGraph from SSA, built
+// over pkgs (a whole-project load from loadWholeProject, shared with
+// buildIndices since CHA/RTA need every package built against one
+// ssa.Program). It always returns a graph; Analyze skips it, leaving
+// ProjectAnalysis.CallGraph nil with a WARN, when loadWholeProject fails.
+func (a *Analyzer) buildCallGraph(pkgAnalyses []*PackageAnalysis, pkgs []*packages.Package) *CallGraph {
+	prog, ssaPkgs := ssautil.AllPackages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+
+	funcPkgPath, funcInfoByPos := indexFunctionInfo(a.projectRoot, pkgAnalyses)
+
+	funcInfoFor := make(map[*ssa.Function]*FunctionInfo)
+	ssaFuncFor := make(map[*FunctionInfo]*ssa.Function)
+	for fn := range ssautil.AllFunctions(prog) {
+		pos := prog.Fset.Position(fn.Pos())
+		if !pos.IsValid() {
+			continue
+		}
+		fi, ok := funcInfoByPos[fileLineKey(pos.Filename, pos.Line)]
+		if !ok {
+			continue
+		}
+		funcInfoFor[fn] = fi
+		ssaFuncFor[fi] = fn
+	}
+
+	var roots []*ssa.Function
+	for _, m := range ssautil.MainPackages(ssaPkgs) {
+		if f := m.Func("main"); f != nil {
+			roots = append(roots, f)
+		}
+	}
+	for _, pa := range pkgAnalyses {
+		for _, fi := range pa.Functions {
+			if fi.HTTPMethod != "" {
+				if f, ok := ssaFuncFor[fi]; ok {
+					roots = append(roots, f)
+				}
+			}
+		}
+		for _, s := range pa.Structs {
+			for _, m := range s.Methods {
+				if m.HTTPMethod != "" {
+					if f, ok := ssaFuncFor[m]; ok {
+						roots = append(roots, f)
+					}
+				}
+			}
+		}
+	}
+
+	var graph *callgraph.Graph
+	algorithm := "cha"
+	if len(roots) > 0 {
+		graph = rta.Analyze(roots, true).CallGraph
+		algorithm = "rta"
+	} else {
+		graph = cha.CallGraph(prog)
+	}
+
+	cg := &CallGraph{
+		Algorithm: algorithm,
+		calleesOf: make(map[*FunctionInfo][]*FunctionInfo),
+		callersOf: make(map[*FunctionInfo][]*FunctionInfo),
+	}
+
+	callgraph.GraphVisitEdges(graph, func(e *callgraph.Edge) error {
+		callerFI, calleeFI := funcInfoFor[e.Caller.Func], funcInfoFor[e.Callee.Func]
+		if callerFI == nil && calleeFI == nil {
+			// Neither end is project code (e.g. two stdlib wrappers
+			// chained together); not useful for Callers/Callees/JSON.
+			return nil
+		}
+
+		dynamic := e.Site != nil && e.Site.Common().StaticCallee() == nil
+
+		var file string
+		var line int
+		if e.Site != nil {
+			if pos := prog.Fset.Position(e.Site.Pos()); pos.IsValid() {
+				file, line = filepath.Base(pos.Filename), pos.Line
+			}
+		}
+
+		calleeNode := callNodeFor(e.Callee.Func, calleeFI, funcPkgPath)
+
+		cg.Edges = append(cg.Edges, &CallEdge{
+			Caller:    callNodeFor(e.Caller.Func, callerFI, funcPkgPath),
+			Callee:    calleeNode,
+			File:      file,
+			Line:      line,
+			IsDynamic: dynamic,
+		})
+
+		if callerFI != nil {
+			callerFI.Callees = append(callerFI.Callees, &CallSite{
+				Callee:   calleeNode.Key,
+				Receiver: calleeNode.Receiver,
+				File:     file,
+				Line:     line,
+				Kind:     callKind(calleeNode, dynamic),
+			})
+		}
+
+		if callerFI != nil && calleeFI != nil {
+			cg.calleesOf[callerFI] = append(cg.calleesOf[callerFI], calleeFI)
+			cg.callersOf[calleeFI] = append(cg.callersOf[calleeFI], callerFI)
+		}
+		return nil
+	})
+
+	return cg
+}
+
+// indexFunctionInfo builds the two lookup tables buildCallGraph needs to
+// attribute an *ssa.Function back to the FunctionInfo already extracted
+// for it: its owning package path, and its source position (file:line
+// of the "func" keyword, which ssa.Function.Pos reports for declared
+// functions too).
+func indexFunctionInfo(
+	projectRoot string,
+	pkgAnalyses []*PackageAnalysis,
+) (map[*FunctionInfo]string, map[string]*FunctionInfo) {
+
+	pkgPath := make(map[*FunctionInfo]string)
+	byPos := make(map[string]*FunctionInfo)
+
+	add := func(pa *PackageAnalysis, fi *FunctionInfo) {
+		pkgPath[fi] = pa.Path
+		full := filepath.Join(projectRoot, pa.Dir, fi.File)
+		byPos[fileLineKey(full, fi.Line)] = fi
+	}
+
+	for _, pa := range pkgAnalyses {
+		for _, fi := range pa.Functions {
+			add(pa, fi)
+		}
+		for _, s := range pa.Structs {
+			for _, m := range s.Methods {
+				add(pa, m)
+			}
+		}
+	}
+
+	return pkgPath, byPos
+}
+
+// callNodeFor renders fn as a CallNode, preferring the already-extracted
+// FunctionInfo (and its package path) when one was matched, and falling
+// back to SSA's own name/package for code outside the project.
+func callNodeFor(fn *ssa.Function, fi *FunctionInfo, funcPkgPath map[*FunctionInfo]string) *CallNode {
+	var node *CallNode
+	if fi != nil {
+		node = &CallNode{Name: fi.Name, Receiver: fi.Receiver, Package: funcPkgPath[fi]}
+	} else {
+		node = &CallNode{Name: fn.Name()}
+		if fn.Pkg != nil && fn.Pkg.Pkg != nil {
+			node.Package = fn.Pkg.Pkg.Path()
+		}
+		if recv := fn.Signature.Recv(); recv != nil {
+			node.Receiver = recv.Type().String()
+		}
+	}
+
+	node.Key = callNodeKey(node.Package, node.Receiver, node.Name)
+	return node
+}
+
+// callNodeKey renders the "pkg.Recv.Method"/"pkg.Name"/bare-"Name" key
+// described on CallNode.Key, stripping the receiver's leading "*" since
+// a node's key identifies the type, not whether this call went through
+// a pointer.
+func callNodeKey(pkgPath, receiver, name string) string {
+	recv := strings.TrimPrefix(receiver, "*")
+	switch {
+	case pkgPath == "":
+		return name
+	case recv == "":
+		return pkgPath + "." + name
+	default:
+		return pkgPath + "." + recv + "." + name
+	}
+}
+
+// builtinFuncs are Go's predeclared builtin functions. callKind uses
+// this set to label a CallSite "builtin" rather than "static", so
+// dead-code and reachability queries over FunctionInfo.Callees can
+// filter out len/append/panic/... noise without a hard-coded allowlist
+// of their own.
+var builtinFuncs = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true,
+	"complex": true, "copy": true, "delete": true, "imag": true,
+	"len": true, "make": true, "max": true, "min": true, "new": true,
+	"panic": true, "print": true, "println": true, "real": true,
+	"recover": true,
+}
+
+// callKind classifies a CallSite: "builtin" for one of Go's predeclared
+// functions (they have no package, so this is unambiguous), "dynamic"
+// for an interface-method or func-value dispatch, "static" otherwise.
+func callKind(callee *CallNode, dynamic bool) string {
+	if callee.Package == "" && callee.Receiver == "" && builtinFuncs[callee.Name] {
+		return "builtin"
+	}
+	if dynamic {
+		return "dynamic"
+	}
+	return "static"
+}
+
+// fileLineKey is the lookup key shared by indexFunctionInfo's table and
+// buildCallGraph's ssa.Function walk: an absolute file path plus 1-based
+// line number.
+func fileLineKey(file string, line int) string {
+	return filepath.Clean(file) + ":" + strconv.Itoa(line)
+}
@@ -0,0 +1,245 @@
+package analysis
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	goanalysis "golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// runAnalyzers runs every registered analyzer against pkg, attaching the
+// resulting diagnostics to pa and to the FunctionInfo they fall within.
+// pkg is already fully type-checked by the go/packages loader (see
+// packagesLoadMode), so analyzers see the same cross-package type facts
+// the compiler would.
+func (a *Analyzer) runAnalyzers(pkg *packages.Package, pa *PackageAnalysis) {
+	if len(a.analyzers) == 0 {
+		return
+	}
+
+	files := pkg.Syntax
+	typesPkg := pkg.Types
+	info := pkg.TypesInfo
+
+	order, err := analysisOrder(a.analyzers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: analyzer dependency cycle: %v\n", err)
+		return
+	}
+
+	facts := newPassFactStore()
+
+	results := make(map[*goanalysis.Analyzer]interface{})
+	for _, an := range order {
+		pass := &goanalysis.Pass{
+			Analyzer:          an,
+			Fset:              a.fset,
+			Files:             files,
+			Pkg:               typesPkg,
+			TypesInfo:         info,
+			ResultOf:          resultsFor(an, results),
+			ExportObjectFact:  facts.exportObjectFact,
+			ImportObjectFact:  facts.importObjectFact,
+			ExportPackageFact: func(fact goanalysis.Fact) { facts.exportPackageFact(typesPkg, fact) },
+			ImportPackageFact: facts.importPackageFact,
+			AllObjectFacts:    facts.allObjectFacts,
+			AllPackageFacts:   facts.allPackageFacts,
+			Report: func(d goanalysis.Diagnostic) {
+				pos := a.fset.Position(d.Pos)
+				di := &DiagnosticInfo{
+					Analyzer: an.Name,
+					Category: an.Doc,
+					Severity: "warning",
+					Message:  d.Message,
+					File:     filepath.Base(pos.Filename),
+					Line:     pos.Line,
+					Col:      pos.Column,
+				}
+				pa.Diagnostics = append(pa.Diagnostics, di)
+				attachToFunction(pa, di)
+			},
+		}
+
+		result, runErr := an.Run(pass)
+		if runErr != nil {
+			fmt.Fprintf(os.Stderr, "WARN: analyzer %s failed on %s: %v\n",
+				an.Name, pa.Path, runErr)
+			continue
+		}
+		results[an] = result
+	}
+}
+
+// passFactStore backs one runAnalyzers call's ExportObjectFact/
+// ImportObjectFact/ExportPackageFact/ImportPackageFact/AllObjectFacts/
+// AllPackageFacts. This driver loads and analyzes one package at a time
+// (see analyzePackageInContext), so there's never a prior pass's
+// serialized facts to import from a dependency - this only has to
+// satisfy analyzers (printf, the built-in example) that export and
+// import facts on objects/packages within the single package being
+// analyzed right now. Shared across every analyzer in a run so that
+// later analyzers in dependency order can see earlier ones' facts, the
+// same way ResultOf does for ordinary results.
+type passFactStore struct {
+	objectFacts  map[types.Object]map[reflect.Type]goanalysis.Fact
+	packageFacts map[*types.Package]map[reflect.Type]goanalysis.Fact
+}
+
+func newPassFactStore() *passFactStore {
+	return &passFactStore{
+		objectFacts:  make(map[types.Object]map[reflect.Type]goanalysis.Fact),
+		packageFacts: make(map[*types.Package]map[reflect.Type]goanalysis.Fact),
+	}
+}
+
+func (fs *passFactStore) exportObjectFact(obj types.Object, fact goanalysis.Fact) {
+	byType, ok := fs.objectFacts[obj]
+	if !ok {
+		byType = make(map[reflect.Type]goanalysis.Fact)
+		fs.objectFacts[obj] = byType
+	}
+	byType[reflect.TypeOf(fact)] = fact
+}
+
+func (fs *passFactStore) importObjectFact(obj types.Object, fact goanalysis.Fact) bool {
+	stored, ok := fs.objectFacts[obj][reflect.TypeOf(fact)]
+	if !ok {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+	return true
+}
+
+func (fs *passFactStore) exportPackageFact(pkg *types.Package, fact goanalysis.Fact) {
+	byType, ok := fs.packageFacts[pkg]
+	if !ok {
+		byType = make(map[reflect.Type]goanalysis.Fact)
+		fs.packageFacts[pkg] = byType
+	}
+	byType[reflect.TypeOf(fact)] = fact
+}
+
+func (fs *passFactStore) importPackageFact(pkg *types.Package, fact goanalysis.Fact) bool {
+	stored, ok := fs.packageFacts[pkg][reflect.TypeOf(fact)]
+	if !ok {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+	return true
+}
+
+func (fs *passFactStore) allObjectFacts() []goanalysis.ObjectFact {
+	out := make([]goanalysis.ObjectFact, 0, len(fs.objectFacts))
+	for obj, byType := range fs.objectFacts {
+		for _, fact := range byType {
+			out = append(out, goanalysis.ObjectFact{Object: obj, Fact: fact})
+		}
+	}
+	return out
+}
+
+func (fs *passFactStore) allPackageFacts() []goanalysis.PackageFact {
+	out := make([]goanalysis.PackageFact, 0, len(fs.packageFacts))
+	for pkg, byType := range fs.packageFacts {
+		for _, fact := range byType {
+			out = append(out, goanalysis.PackageFact{Package: pkg, Fact: fact})
+		}
+	}
+	return out
+}
+
+// attachToFunction appends di to the FunctionInfo (free function or
+// method) whose line range contains it.
+func attachToFunction(pa *PackageAnalysis, di *DiagnosticInfo) {
+	assign := func(fi *FunctionInfo) bool {
+		if fi.File != di.File {
+			return false
+		}
+		end := fi.EndLine
+		if end == 0 {
+			end = fi.Line
+		}
+		if di.Line >= fi.Line && di.Line <= end {
+			fi.Diagnostics = append(fi.Diagnostics, di)
+			return true
+		}
+		return false
+	}
+
+	for _, fi := range pa.Functions {
+		if assign(fi) {
+			return
+		}
+	}
+	for _, s := range pa.Structs {
+		for _, m := range s.Methods {
+			if assign(m) {
+				return
+			}
+		}
+	}
+}
+
+// resultsFor builds the ResultOf map an analyzer expects, pulling only
+// the dependencies it declared via Requires.
+func resultsFor(
+	an *goanalysis.Analyzer,
+	all map[*goanalysis.Analyzer]interface{},
+) map[*goanalysis.Analyzer]interface{} {
+
+	out := make(map[*goanalysis.Analyzer]interface{}, len(an.Requires))
+	for _, req := range an.Requires {
+		if v, ok := all[req]; ok {
+			out[req] = v
+		}
+	}
+	return out
+}
+
+// analysisOrder returns analyzers sorted so that every analyzer appears
+// after everything it Requires, via a depth-first topological sort.
+func analysisOrder(analyzers []*goanalysis.Analyzer) ([]*goanalysis.Analyzer, error) {
+	var order []*goanalysis.Analyzer
+	state := make(map[*goanalysis.Analyzer]int) // 0=unvisited 1=visiting 2=done
+
+	var visit func(an *goanalysis.Analyzer) error
+	visit = func(an *goanalysis.Analyzer) error {
+		switch state[an] {
+		case 2:
+			return nil
+		case 1:
+			return fmt.Errorf("cycle at analyzer %s", an.Name)
+		}
+		state[an] = 1
+		for _, dep := range an.Requires {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[an] = 2
+		order = append(order, an)
+		return nil
+	}
+
+	for _, an := range analyzers {
+		if err := visit(an); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// analyzerByName looks up a registered analyzer by its Name field,
+// letting the CLI select a subset via -analyzer=name.
+func analyzerByName(analyzers []*goanalysis.Analyzer, name string) (*goanalysis.Analyzer, bool) {
+	for _, an := range analyzers {
+		if an.Name == name {
+			return an, true
+		}
+	}
+	return nil, false
+}
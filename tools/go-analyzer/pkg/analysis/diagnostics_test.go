@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/passes/printf"
+)
+
+// createPrintfWrapperProject creates a project containing an ordinary
+// variadic printf-wrapper (a common logging pattern), the shape printf's
+// own fact-based wrapper detection (findPrintfLike) exercises.
+func createPrintfWrapperProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	writeFile(t, dir, "go.mod", `module github.com/test/printfapp
+
+go 1.22
+`)
+
+	writeFile(t, dir, "logging.go", `package main
+
+import "fmt"
+
+// Logf forwards to fmt.Printf, the classic printf-wrapper shape.
+func Logf(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+func main() {
+	Logf("hello %s", "world")
+}
+`)
+
+	return dir
+}
+
+// TestAnalyzer_PrintfAnalyzerDoesNotPanic guards against runAnalyzers
+// building a *goanalysis.Pass with nil ExportObjectFact/ImportObjectFact/
+// ExportPackageFact/ImportPackageFact/AllObjectFacts/AllPackageFacts:
+// printf (a builtinAnalyzers entry, see cmd/analyzer/main.go) calls
+// ExportObjectFact while detecting printf-wrapper functions like Logf
+// above, and previously panicked with a nil-pointer dereference the
+// instant it ran.
+func TestAnalyzer_PrintfAnalyzerDoesNotPanic(t *testing.T) {
+	dir := createPrintfWrapperProject(t)
+
+	a := NewAnalyzer(dir, WithAnalyzers(printf.Analyzer), WithNoCache())
+	if _, err := a.Analyze(); err != nil {
+		t.Fatalf("Analyze() with printf analyzer failed: %v", err)
+	}
+}
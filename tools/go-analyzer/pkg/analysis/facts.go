@@ -0,0 +1,186 @@
+package analysis
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Fact is a typed property exported about a declaration (today, a
+// struct or function/method) that downstream packages can import when
+// they reference it. Modeled on golang.org/x/tools/go/analysis's Fact
+// mechanism, but keyed by a package-qualified "pkgPath.Type.Method"
+// string rather than an objectpath.Path, since that's what's available
+// at the point facts are exported (one package extraction at a time,
+// ahead of the whole-project go/types pass buildIndices runs later).
+type Fact interface {
+	AFact()
+}
+
+// HandlerFact marks a function as an HTTP (or equivalent) entry point.
+type HandlerFact struct {
+	HTTPMethod string
+}
+
+// AFact implements Fact.
+func (*HandlerFact) AFact() {}
+
+// RepositoryFact marks a struct as belonging to the REPOSITORY layer.
+type RepositoryFact struct{}
+
+// AFact implements Fact.
+func (*RepositoryFact) AFact() {}
+
+// PanicsFact marks a function as (possibly) panicking.
+type PanicsFact struct{}
+
+// AFact implements Fact.
+func (*PanicsFact) AFact() {}
+
+// FactSet stores facts exported by analyzed declarations, keyed by a
+// qualified name, so they can be imported by any other package in the
+// project regardless of load order. Safe for concurrent use.
+type FactSet struct {
+	mu    sync.Mutex
+	facts map[string]map[string]Fact // key -> fact type name -> fact
+}
+
+// NewFactSet creates an empty FactSet.
+func NewFactSet() *FactSet {
+	return &FactSet{facts: make(map[string]map[string]Fact)}
+}
+
+// Export records fact under key, keyed additionally by its concrete
+// type so a declaration can hold more than one kind of fact.
+func (fs *FactSet) Export(key string, fact Fact) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	byType, ok := fs.facts[key]
+	if !ok {
+		byType = make(map[string]Fact)
+		fs.facts[key] = byType
+	}
+	byType[factTypeName(fact)] = fact
+}
+
+// Has reports whether key has a fact of the same concrete type as
+// sample.
+func (fs *FactSet) Has(key string, sample Fact) bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	byType, ok := fs.facts[key]
+	if !ok {
+		return false
+	}
+	_, ok = byType[factTypeName(sample)]
+	return ok
+}
+
+// FactsFor returns every fact exported under key.
+func (fs *FactSet) FactsFor(key string) []Fact {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	byType, ok := fs.facts[key]
+	if !ok {
+		return nil
+	}
+	out := make([]Fact, 0, len(byType))
+	for _, f := range byType {
+		out = append(out, f)
+	}
+	return out
+}
+
+func factTypeName(fact Fact) string {
+	return fmt.Sprintf("%T", fact)
+}
+
+// exportFacts publishes the direct (non-transitive) facts observable
+// from a single package's analysis: repository structs, panicking
+// methods, and HTTP handlers. Keys are qualified with pa.Path so that
+// two packages declaring same-named structs or methods (e.g. two
+// Repository.Save) don't share a FactSet entry.
+func (a *Analyzer) exportFacts(pa *PackageAnalysis) {
+	if pa.ClassType == "REPOSITORY" {
+		for _, s := range pa.Structs {
+			a.facts.Export(pa.Path+"."+s.Name, &RepositoryFact{})
+		}
+	}
+
+	exportFn := func(key string, fi *FunctionInfo) {
+		if fi.HasPanic {
+			a.facts.Export(key, &PanicsFact{})
+		}
+		if fi.HTTPMethod != "" {
+			a.facts.Export(key, &HandlerFact{HTTPMethod: fi.HTTPMethod})
+		}
+	}
+
+	for _, f := range pa.Functions {
+		exportFn(pa.Path+"."+f.Name, f)
+	}
+	for _, s := range pa.Structs {
+		for _, m := range s.Methods {
+			exportFn(pa.Path+"."+s.Name+"."+m.Name, m)
+		}
+	}
+}
+
+// propagateFacts runs a fixed-point pass over every function/method in
+// the project, propagating PanicsFact upward through call chains
+// (caller inherits TransitivelyPanics from any callee that panics) and
+// HandlerFact reachability downward (callees of an HTTP handler are
+// marked HTTPReachable). Iterates until nothing changes, bounded by the
+// total number of functions to guarantee termination on call cycles.
+// Keys are qualified with each function's/method's package path (see
+// exportFacts), matching the already-qualified callee keys resolveFieldCalls
+// produces from each field's fully-qualified go/types.Type string.
+func (a *Analyzer) propagateFacts(packages []*PackageAnalysis) {
+	var all []*FunctionInfo
+	keyOf := make(map[*FunctionInfo]string)
+
+	for _, pa := range packages {
+		for _, f := range pa.Functions {
+			all = append(all, f)
+			keyOf[f] = pa.Path + "." + f.Name
+		}
+		for _, s := range pa.Structs {
+			for _, m := range s.Methods {
+				all = append(all, m)
+				keyOf[m] = pa.Path + "." + s.Name + "." + m.Name
+			}
+		}
+	}
+
+	for i := 0; i <= len(all); i++ {
+		changed := false
+
+		for _, fi := range all {
+			key := keyOf[fi]
+			isHandler := fi.HTTPMethod != "" || a.facts.Has(key, &HandlerFact{})
+
+			for _, callKey := range fi.calls {
+				if !fi.TransitivelyPanics && (a.facts.Has(callKey, &PanicsFact{})) {
+					fi.TransitivelyPanics = true
+					a.facts.Export(key, &PanicsFact{})
+					changed = true
+				}
+				if isHandler && !a.facts.Has(callKey, &HandlerFact{}) {
+					a.facts.Export(callKey, &HandlerFact{})
+					changed = true
+				}
+			}
+
+			if a.facts.Has(key, &HandlerFact{}) && !fi.HTTPReachable {
+				fi.HTTPReachable = true
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// RouteInfo describes one route, RPC, or message-queue subscription
+// registration discovered by a FrameworkDetector.
+type RouteInfo struct {
+	// Framework identifies the detector that found this route (e.g.
+	// "gin", "grpc", "kafka"); see defaultDetectors.
+	Framework string `json:"framework"`
+
+	// Method is the HTTP verb for router frameworks, the
+	// RegisterXxxServer function name for gRPC registrations, and empty
+	// for message-queue registrations.
+	Method string `json:"method,omitempty"`
+
+	// Path is the route path, topic, or queue name, when the
+	// registration call passes it as a string literal.
+	Path string `json:"path,omitempty"`
+
+	// Handler is the resolved handler/service name: a bare function or
+	// method name when info let us resolve the concrete *types.Func,
+	// otherwise the handler expression as rendered by exprToString.
+	Handler string `json:"handler,omitempty"`
+
+	File string `json:"file"`
+	Line int    `json:"line"`
+
+	// pos is resolved to File/Line by collectRoutes once the route
+	// leaves the detector, since Match only sees the parsed *ast.File
+	// and has no *token.FileSet of its own.
+	pos token.Pos
+}
+
+// FrameworkDetector finds registration call sites for one specific
+// framework (an HTTP router, a gRPC service, a message-queue consumer)
+// within a single file. Register custom ones via NewAnalyzer's
+// WithDetector option.
+type FrameworkDetector interface {
+	// Name identifies the detector; used as RouteInfo.Framework.
+	Name() string
+
+	// Match walks file for this framework's registration calls, using
+	// info (may be nil) to resolve receiver and argument types.
+	Match(file *ast.File, info *types.Info) []RouteInfo
+}
+
+// defaultDetectors is the built-in set every Analyzer starts with;
+// WithDetector appends to it rather than replacing it.
+func defaultDetectors() []FrameworkDetector {
+	return []FrameworkDetector{
+		netHTTPDetector{},
+		routerDetector{framework: "gin", pkgPath: "github.com/gin-gonic/gin", verbs: routerVerbs},
+		routerDetector{framework: "echo", pkgPath: "github.com/labstack/echo/v4", verbs: routerVerbs},
+		routerDetector{framework: "fiber", pkgPath: "github.com/gofiber/fiber/v2", verbs: routerVerbs},
+		routerDetector{framework: "chi", pkgPath: "github.com/go-chi/chi/v5", verbs: chiVerbs},
+		routerDetector{framework: "gorilla/mux", pkgPath: "github.com/gorilla/mux", verbs: muxVerbs},
+		grpcDetector{},
+		mqDetector{framework: "kafka", pkgPaths: []string{
+			"github.com/segmentio/kafka-go",
+			"github.com/confluentinc/confluent-kafka-go/kafka",
+		}},
+		mqDetector{framework: "nats", pkgPaths: []string{"github.com/nats-io/nats.go"}},
+		mqDetector{framework: "amqp", pkgPaths: []string{
+			"github.com/rabbitmq/amqp091-go",
+			"github.com/streadway/amqp",
+		}},
+	}
+}
+
+// routerVerbs maps the upper-case registration method names gin and
+// echo share to the HTTP verb they register. fiber's detector also uses
+// this map, but fiber registers routes via Get/Post/..., so its
+// registrations are not matched.
+var routerVerbs = map[string]string{
+	"GET": "GET", "POST": "POST", "PUT": "PUT", "PATCH": "PATCH",
+	"DELETE": "DELETE", "HEAD": "HEAD", "OPTIONS": "OPTIONS",
+	"Any": "ANY",
+}
+
+// chiVerbs maps chi.Router's registration method names to the HTTP verb
+// they register.
+var chiVerbs = map[string]string{
+	"Get": "GET", "Post": "POST", "Put": "PUT", "Patch": "PATCH",
+	"Delete": "DELETE", "Head": "HEAD", "Options": "OPTIONS",
+}
+
+// muxVerbs covers gorilla/mux's registration method; the verb itself is
+// set separately via a chained .Methods("GET") call that this detector
+// does not attempt to parse, so Method is left empty.
+var muxVerbs = map[string]string{"HandleFunc": "", "Handle": ""}
+
+// mqConsumeMethods are the subscribe/consume method names this package
+// looks for on a message-queue client, regardless of broker.
+var mqConsumeMethods = map[string]bool{
+	"Subscribe": true, "SubscribeSync": true, "QueueSubscribe": true, "Consume": true,
+}
+
+// routerDetector implements FrameworkDetector for routers whose
+// registration calls look like recv.Verb(path, handler), where recv's
+// static type is declared in pkgPath (e.g. gin.Engine/RouterGroup,
+// echo.Echo/Group, fiber.App, chi.Router).
+type routerDetector struct {
+	framework string
+	pkgPath   string
+	verbs     map[string]string
+}
+
+func (d routerDetector) Name() string { return d.framework }
+
+func (d routerDetector) Match(file *ast.File, info *types.Info) []RouteInfo {
+	var routes []RouteInfo
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		verb, known := d.verbs[sel.Sel.Name]
+		if !known || !receiverInPackage(sel.X, info, d.pkgPath) {
+			return true
+		}
+		if len(call.Args) == 0 {
+			return true
+		}
+
+		routes = append(routes, RouteInfo{
+			Framework: d.framework,
+			Method:    verb,
+			Path:      stringLiteral(call.Args[0]),
+			Handler:   resolveHandlerName(call.Args[len(call.Args)-1], info),
+			pos:       call.Pos(),
+		})
+		return true
+	})
+
+	return routes
+}
+
+// netHTTPDetector matches http.HandleFunc/http.Handle package-level
+// calls and the equivalent methods on *http.ServeMux.
+type netHTTPDetector struct{}
+
+func (netHTTPDetector) Name() string { return "net/http" }
+
+func (netHTTPDetector) Match(file *ast.File, info *types.Info) []RouteInfo {
+	var routes []RouteInfo
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if sel.Sel.Name != "HandleFunc" && sel.Sel.Name != "Handle" {
+			return true
+		}
+		if !isPackageSelector(sel.X, info, "net/http") && !receiverInPackage(sel.X, info, "net/http") {
+			return true
+		}
+		if len(call.Args) < 2 {
+			return true
+		}
+
+		routes = append(routes, RouteInfo{
+			Framework: "net/http",
+			Path:      stringLiteral(call.Args[0]),
+			Handler:   resolveHandlerName(call.Args[1], info),
+			pos:       call.Pos(),
+		})
+		return true
+	})
+
+	return routes
+}
+
+// grpcDetector matches generated-code registration calls of the shape
+// RegisterXxxServer(server, impl) - the naming convention protoc-gen-go-grpc
+// emits for every service - without depending on any specific generated
+// package, since those are project-local and can't be named in advance.
+type grpcDetector struct{}
+
+func (grpcDetector) Name() string { return "grpc" }
+
+func (grpcDetector) Match(file *ast.File, info *types.Info) []RouteInfo {
+	var routes []RouteInfo
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		var funcName string
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			funcName = fn.Name
+		case *ast.SelectorExpr:
+			funcName = fn.Sel.Name
+		default:
+			return true
+		}
+		if !strings.HasPrefix(funcName, "Register") || !strings.HasSuffix(funcName, "Server") {
+			return true
+		}
+
+		handler := ""
+		if len(call.Args) > 0 {
+			handler = resolveHandlerName(call.Args[len(call.Args)-1], info)
+		}
+
+		routes = append(routes, RouteInfo{
+			Framework: "grpc",
+			Method:    funcName,
+			Handler:   handler,
+			pos:       call.Pos(),
+		})
+		return true
+	})
+
+	return routes
+}
+
+// mqDetector matches subscribe/consume calls on a message-queue client
+// whose static type is declared in one of pkgPaths.
+type mqDetector struct {
+	framework string
+	pkgPaths  []string
+}
+
+func (d mqDetector) Name() string { return d.framework }
+
+func (d mqDetector) Match(file *ast.File, info *types.Info) []RouteInfo {
+	var routes []RouteInfo
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !mqConsumeMethods[sel.Sel.Name] {
+			return true
+		}
+
+		matched := false
+		for _, pkgPath := range d.pkgPaths {
+			if receiverInPackage(sel.X, info, pkgPath) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return true
+		}
+
+		topic := ""
+		if len(call.Args) > 0 {
+			topic = stringLiteral(call.Args[0])
+		}
+		handler := ""
+		if len(call.Args) > 1 {
+			handler = resolveHandlerName(call.Args[len(call.Args)-1], info)
+		}
+
+		routes = append(routes, RouteInfo{
+			Framework: d.framework,
+			Path:      topic,
+			Handler:   handler,
+			pos:       call.Pos(),
+		})
+		return true
+	})
+
+	return routes
+}
+
+// receiverInPackage reports whether expr's static type (after stripping
+// one level of pointer) is a named type declared in pkgPath.
+func receiverInPackage(expr ast.Expr, info *types.Info, pkgPath string) bool {
+	if info == nil {
+		return false
+	}
+	t := info.TypeOf(expr)
+	if t == nil {
+		return false
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj() == nil || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkgPath
+}
+
+// isPackageSelector reports whether expr is a bare identifier referring
+// to the imported package pkgPath itself (e.g. the "http" in
+// http.HandleFunc), rather than a value of a type declared there.
+func isPackageSelector(expr ast.Expr, info *types.Info, pkgPath string) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || info == nil {
+		return false
+	}
+	pn, ok := info.Uses[ident].(*types.PkgName)
+	if !ok {
+		return false
+	}
+	return pn.Imported().Path() == pkgPath
+}
+
+// stringLiteral returns expr's value when it is a plain string literal,
+// and "" otherwise (e.g. a path built from a variable or fmt.Sprintf).
+func stringLiteral(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	return strings.Trim(lit.Value, `"`+"`")
+}
+
+// resolveHandlerName renders a registration call's handler argument:
+// the bare method name when expr is a method value info could resolve
+// (e.g. "CreateOrder" for svc.CreateOrder, regardless of the receiver
+// variable's name), falling back to plain AST-string rendering when no
+// type info is available or expr isn't a method value.
+func resolveHandlerName(expr ast.Expr, info *types.Info) string {
+	if info != nil {
+		if sel, ok := expr.(*ast.SelectorExpr); ok {
+			if selection, ok := info.Selections[sel]; ok {
+				if fn, ok := selection.Obj().(*types.Func); ok {
+					return fn.Name()
+				}
+			}
+		}
+	}
+	return exprToString(expr)
+}
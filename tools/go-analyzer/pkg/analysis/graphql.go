@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gqlgenSchema holds the GraphQL root-type field names extracted from a
+// gqlgen project's schema.graphql/*.graphqls files, used to recognize
+// resolver methods that have no HTTP signature of their own.
+type gqlgenSchema struct {
+	// fields maps a root type name ("Query", "Mutation", "Subscription")
+	// to the set of field names declared on it, exactly as written in
+	// the schema (lowerCamelCase).
+	fields map[string]map[string]bool
+}
+
+// rootResolverTypes maps the unexported resolver struct names gqlgen
+// generates for each operation root to that root's type name in the
+// schema.
+var rootResolverTypes = map[string]string{
+	"queryResolver":        "Query",
+	"mutationResolver":     "Mutation",
+	"subscriptionResolver": "Subscription",
+}
+
+// loadGQLGenSchema looks for gqlgen.yml at the project root and, if
+// found, parses every schema.graphql/*.graphqls file in the project for
+// Query/Mutation/Subscription field declarations. Returns nil if the
+// project doesn't use gqlgen, so attachGraphQLResolvers becomes a no-op.
+func (a *Analyzer) loadGQLGenSchema() *gqlgenSchema {
+	if _, err := os.Stat(filepath.Join(a.projectRoot, "gqlgen.yml")); err != nil {
+		return nil
+	}
+
+	schema := &gqlgenSchema{fields: make(map[string]map[string]bool)}
+	_ = filepath.Walk(a.projectRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if isExcludedDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := info.Name()
+		if name != "schema.graphql" && !strings.HasSuffix(name, ".graphqls") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		parseGraphQLSchema(string(data), schema.fields)
+		return nil
+	})
+
+	return schema
+}
+
+// parseGraphQLSchema does a line-oriented scan for "type Query { ... }"
+// style blocks (Query/Mutation/Subscription only) and records each
+// field's name into out, ignoring its arguments and return type. Good
+// enough for matching resolver methods by name; anything needing real
+// SDL validation should go through gqlgen's own tooling instead.
+func parseGraphQLSchema(src string, out map[string]map[string]bool) {
+	current := ""
+
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if current == "" {
+			if !strings.HasSuffix(line, "{") {
+				continue
+			}
+			header := strings.Fields(strings.TrimSuffix(line, "{"))
+			if len(header) < 2 || header[0] != "type" || !isRootTypeName(header[1]) {
+				continue
+			}
+			current = header[1]
+			if out[current] == nil {
+				out[current] = make(map[string]bool)
+			}
+			continue
+		}
+
+		if line == "}" {
+			current = ""
+			continue
+		}
+
+		field := line
+		if i := strings.IndexAny(field, "(:"); i >= 0 {
+			field = field[:i]
+		}
+		field = strings.TrimSpace(field)
+		if field != "" {
+			out[current][field] = true
+		}
+	}
+}
+
+// isRootTypeName reports whether name is one of the GraphQL operation
+// root types gqlgen generates a dedicated resolver struct for.
+func isRootTypeName(name string) bool {
+	return name == "Query" || name == "Mutation" || name == "Subscription"
+}
+
+// attachGraphQLResolvers matches methods on gqlgen's generated resolver
+// structs (queryResolver, mutationResolver, subscriptionResolver)
+// against a.gqlSchema's field names and stamps the synthetic HTTPMethod
+// "GRAPHQL"/HTTPPath a regular HTTP handler would carry, so these
+// packages classify as CONTROLLER instead of falling through to OTHER.
+// A no-op when the project isn't a gqlgen project (a.gqlSchema == nil).
+func (a *Analyzer) attachGraphQLResolvers(pa *PackageAnalysis) {
+	if a.gqlSchema == nil {
+		return
+	}
+
+	for _, s := range pa.Structs {
+		root, ok := rootResolverTypes[s.Name]
+		if !ok {
+			continue
+		}
+		fields := a.gqlSchema.fields[root]
+		if fields == nil {
+			continue
+		}
+
+		for _, m := range s.Methods {
+			field := lowerFirst(m.Name)
+			if !fields[field] {
+				continue
+			}
+			m.HTTPMethod = "GRAPHQL"
+			m.HTTPPath = "/graphql#" + root + "." + field
+		}
+	}
+}
+
+// lowerFirst lower-cases the first rune of s, mirroring the
+// capitalization gqlgen applies when turning a lowerCamelCase schema
+// field name into an exported Go method name.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
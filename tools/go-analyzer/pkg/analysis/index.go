@@ -0,0 +1,305 @@
+package analysis
+
+import (
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Ref is a single source-level mention of a declared struct, interface,
+// or function: a field/parameter/return type, a method call, or a
+// composite literal.
+type Ref struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Col  int    `json:"col"`
+
+	// Kind is "type" (field/param/return/literal type position) or
+	// "call" (a function or method invocation).
+	Kind string `json:"kind"`
+}
+
+// FindImplementers returns the structs known to implement the interface
+// named by its fully-qualified path ("pkgPath.IfaceName"), from the most
+// recent Analyze call.
+func (a *Analyzer) FindImplementers(iface string) []*StructInfo {
+	return a.implementers[iface]
+}
+
+// FindReferences returns every recorded reference to the struct,
+// interface, or function named by its fully-qualified path
+// ("pkgPath.Name"), from the most recent Analyze call.
+func (a *Analyzer) FindReferences(qname string) []*Ref {
+	return a.references[qname]
+}
+
+// buildIndices computes ProjectAnalysis.Implementers and .References
+// over pkgs (a whole-project load shared with buildCallGraph), and
+// caches both on the Analyzer for FindImplementers/FindReferences.
+func (a *Analyzer) buildIndices(pkgAnalyses []*PackageAnalysis, pkgs []*packages.Package, result *ProjectAnalysis) {
+	structByQName, ifaceByQName, funcByQName, methodByQName := qnameIndex(pkgAnalyses)
+
+	result.Implementers = findImplementers(pkgs, a.modulePath, structByQName, ifaceByQName)
+	result.References = findReferences(a.fset, pkgs, a.modulePath, structByQName, ifaceByQName, funcByQName, methodByQName)
+	populateUsedBy(pkgAnalyses, structByQName, ifaceByQName)
+
+	a.implementers = result.Implementers
+	a.references = result.References
+}
+
+// qnameIndex maps every declared struct, interface, free function, and
+// method to its fully-qualified name ("pkgPath.Name", or
+// "pkgPath.Receiver.Method" for methods).
+func qnameIndex(pkgAnalyses []*PackageAnalysis) (
+	structs map[string]*StructInfo,
+	ifaces map[string]*InterfaceInfo,
+	funcs map[string]*FunctionInfo,
+	methods map[string]*FunctionInfo,
+) {
+	structs = make(map[string]*StructInfo)
+	ifaces = make(map[string]*InterfaceInfo)
+	funcs = make(map[string]*FunctionInfo)
+	methods = make(map[string]*FunctionInfo)
+
+	for _, pa := range pkgAnalyses {
+		for _, s := range pa.Structs {
+			structs[pa.Path+"."+s.Name] = s
+			for _, m := range s.Methods {
+				recv := strings.TrimPrefix(m.Receiver, "*")
+				methods[pa.Path+"."+recv+"."+m.Name] = m
+			}
+		}
+		for _, it := range pa.Interfaces {
+			ifaces[pa.Path+"."+it.Name] = it
+		}
+		for _, f := range pa.Functions {
+			funcs[pa.Path+"."+f.Name] = f
+		}
+	}
+
+	return structs, ifaces, funcs, methods
+}
+
+// findImplementers checks every in-module named struct type against
+// every in-module named interface type via types.Implements, trying
+// both value and pointer receiver sets, and records the result on the
+// matching StructInfo/InterfaceInfo (Implements/ImplementedBy) as well
+// as returning it keyed by interface.
+//
+// Generic (type-parameterized) declarations are skipped: types.Implements
+// operates on a single instantiation, and the analysis here has no
+// particular instantiation to check against, so a generic struct or
+// interface simply never appears as a match in either direction.
+func findImplementers(
+	pkgs []*packages.Package,
+	modulePath string,
+	structByQName map[string]*StructInfo,
+	ifaceByQName map[string]*InterfaceInfo,
+) map[string][]*StructInfo {
+
+	type named struct {
+		typ     *types.Named
+		pkgPath string
+	}
+	var structTypes, ifaceTypes []named
+
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || !strings.HasPrefix(pkg.PkgPath, modulePath) {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			nt, ok := tn.Type().(*types.Named)
+			if !ok || nt.TypeParams().Len() > 0 {
+				continue
+			}
+			switch nt.Underlying().(type) {
+			case *types.Interface:
+				ifaceTypes = append(ifaceTypes, named{nt, pkg.PkgPath})
+			case *types.Struct:
+				structTypes = append(structTypes, named{nt, pkg.PkgPath})
+			}
+		}
+	}
+
+	implementers := make(map[string][]*StructInfo)
+	for _, ifaceEntry := range ifaceTypes {
+		ifaceType, ok := ifaceEntry.typ.Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		ifaceQName := ifaceEntry.pkgPath + "." + ifaceEntry.typ.Obj().Name()
+		ii := ifaceByQName[ifaceQName]
+
+		for _, structEntry := range structTypes {
+			structQName := structEntry.pkgPath + "." + structEntry.typ.Obj().Name()
+			si, ok := structByQName[structQName]
+			if !ok {
+				continue
+			}
+			if types.Implements(structEntry.typ, ifaceType) ||
+				types.Implements(types.NewPointer(structEntry.typ), ifaceType) {
+				implementers[ifaceQName] = append(implementers[ifaceQName], si)
+				si.Implements = append(si.Implements, ifaceQName)
+				if ii != nil {
+					ii.ImplementedBy = append(ii.ImplementedBy, structQName)
+				}
+			}
+		}
+	}
+
+	return implementers
+}
+
+// populateUsedBy scans every free function and method across
+// pkgAnalyses and, for each parameter whose type resolves to an
+// in-module struct or interface, appends the function/method's
+// fully-qualified name to that struct's or interface's UsedBy.
+func populateUsedBy(
+	pkgAnalyses []*PackageAnalysis,
+	structByQName map[string]*StructInfo,
+	ifaceByQName map[string]*InterfaceInfo,
+) {
+	for _, pa := range pkgAnalyses {
+		var funcs []*FunctionInfo
+		funcs = append(funcs, pa.Functions...)
+		for _, s := range pa.Structs {
+			funcs = append(funcs, s.Methods...)
+		}
+
+		for _, fi := range funcs {
+			qname := pa.Path + "." + fi.Name
+			if fi.Receiver != "" {
+				qname = pa.Path + "." + strings.TrimPrefix(fi.Receiver, "*") + "." + fi.Name
+			}
+
+			for _, p := range fi.Params {
+				if p.Package == "" {
+					continue
+				}
+				paramQName := p.Package + "." + paramBareTypeName(p.Type)
+				if si, ok := structByQName[paramQName]; ok {
+					si.UsedBy = append(si.UsedBy, qname)
+				}
+				if ii, ok := ifaceByQName[paramQName]; ok {
+					ii.UsedBy = append(ii.UsedBy, qname)
+				}
+			}
+		}
+	}
+}
+
+// paramBareTypeName strips pointer, slice, and variadic wrappers plus
+// any package qualifier from a ParamInfo.Type string (e.g.
+// "...*github.com/user/repo/pkg.Order" becomes "Order"), leaving the
+// bare type name to combine with ParamInfo.Package into a qualified
+// name.
+func paramBareTypeName(typ string) string {
+	typ = strings.TrimPrefix(typ, "...")
+	typ = strings.TrimPrefix(typ, "[]")
+	typ = strings.TrimPrefix(typ, "*")
+	if i := strings.LastIndex(typ, "."); i >= 0 {
+		typ = typ[i+1:]
+	}
+	return typ
+}
+
+// findReferences walks every Uses and Selections entry in each
+// in-module package's type-checked info, recording a Ref wherever an
+// identifier resolves to a declared struct, interface, free function,
+// or method.
+func findReferences(
+	fset *token.FileSet,
+	pkgs []*packages.Package,
+	modulePath string,
+	structByQName map[string]*StructInfo,
+	ifaceByQName map[string]*InterfaceInfo,
+	funcByQName map[string]*FunctionInfo,
+	methodByQName map[string]*FunctionInfo,
+) map[string][]*Ref {
+
+	references := make(map[string][]*Ref)
+	record := func(qname string, pos token.Position, kind string) {
+		references[qname] = append(references[qname], &Ref{
+			File: filepath.Base(pos.Filename),
+			Line: pos.Line,
+			Col:  pos.Column,
+			Kind: kind,
+		})
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil || !strings.HasPrefix(pkg.PkgPath, modulePath) {
+			continue
+		}
+		info := pkg.TypesInfo
+
+		for ident, obj := range info.Uses {
+			qname := qualifiedName(obj)
+			if qname == "" {
+				continue
+			}
+			_, isStruct := structByQName[qname]
+			_, isIface := ifaceByQName[qname]
+			_, isFunc := funcByQName[qname]
+			if !isStruct && !isIface && !isFunc {
+				continue
+			}
+
+			kind := "type"
+			if isFunc {
+				kind = "call"
+			}
+			record(qname, fset.Position(ident.Pos()), kind)
+		}
+
+		for sel, selection := range info.Selections {
+			fn, ok := selection.Obj().(*types.Func)
+			if !ok {
+				continue
+			}
+			recv, ok := namedReceiver(fn)
+			if !ok {
+				continue
+			}
+			qname := recv.Obj().Pkg().Path() + "." + recv.Obj().Name() + "." + fn.Name()
+			if _, ok := methodByQName[qname]; !ok {
+				continue
+			}
+			record(qname, fset.Position(sel.Sel.Pos()), "call")
+		}
+	}
+
+	return references
+}
+
+// qualifiedName returns obj's "pkgPath.Name", or "" for objects with no
+// package (builtins, universe scope).
+func qualifiedName(obj types.Object) string {
+	if obj == nil || obj.Pkg() == nil {
+		return ""
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// namedReceiver unwraps fn's receiver type (stripping a leading pointer)
+// down to the *types.Named it's declared on.
+func namedReceiver(fn *types.Func) (*types.Named, bool) {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return nil, false
+	}
+	t := sig.Recv().Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return named, ok
+}
@@ -0,0 +1,65 @@
+package analysis
+
+import goanalysis "golang.org/x/tools/go/analysis"
+
+// Option configures optional behavior on an Analyzer. Passed to
+// NewAnalyzer.
+type Option func(*Analyzer)
+
+// WithAnalyzers registers go/analysis analyzers (vet checks,
+// staticcheck, or custom in-tree analyzers) to run against every
+// discovered package. Findings are attached to PackageAnalysis.Diagnostics
+// and, where the position falls inside a function body, to that
+// function's FunctionInfo.Diagnostics.
+func WithAnalyzers(analyzers ...*goanalysis.Analyzer) Option {
+	return func(a *Analyzer) {
+		a.analyzers = append(a.analyzers, analyzers...)
+	}
+}
+
+// WithPackageCallback registers a function invoked as soon as each
+// package finishes loading, from whichever goroutine completed it. This
+// lets callers (e.g. watch mode) stream results incrementally instead of
+// waiting for the whole project to finish. fn must be safe to call
+// concurrently.
+func WithPackageCallback(fn func(*PackageAnalysis)) Option {
+	return func(a *Analyzer) {
+		a.onPackage = fn
+	}
+}
+
+// WithCacheDir overrides where the on-disk analysis cache is stored
+// (default: cache.DefaultDir()). No effect if combined with
+// WithNoCache.
+func WithCacheDir(dir string) Option {
+	return func(a *Analyzer) {
+		a.cacheDir = dir
+	}
+}
+
+// WithNoCache disables the on-disk analysis cache entirely: every
+// package is re-parsed on every Analyze call.
+func WithNoCache() Option {
+	return func(a *Analyzer) {
+		a.noCache = true
+	}
+}
+
+// WithDetector registers additional FrameworkDetectors alongside the
+// built-in set (see defaultDetectors), for frameworks this package
+// doesn't recognize out of the box.
+func WithDetector(detectors ...FrameworkDetector) Option {
+	return func(a *Analyzer) {
+		a.detectors = append(a.detectors, detectors...)
+	}
+}
+
+// WithBuildMatrix overrides the set of GOOS/GOARCH/cgo contexts every
+// package is analyzed under (default: defaultBuildMatrix()). The first
+// context becomes each package's canonical view; the rest only
+// contribute a Variants entry when their file set actually diverges.
+func WithBuildMatrix(contexts ...BuildContext) Option {
+	return func(a *Analyzer) {
+		a.buildMatrix = contexts
+	}
+}
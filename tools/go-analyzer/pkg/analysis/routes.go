@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+	"path/filepath"
+)
+
+// collectRoutes runs every registered FrameworkDetector over file and
+// resolves each RouteInfo's source position via a.fset, since Match only
+// sees the parsed *ast.File and has no *token.FileSet of its own.
+func (a *Analyzer) collectRoutes(file *ast.File, info *types.Info) []*RouteInfo {
+	var routes []*RouteInfo
+
+	for _, d := range a.detectors {
+		for _, r := range d.Match(file, info) {
+			r := r
+			pos := a.fset.Position(r.pos)
+			r.File = filepath.Base(pos.Filename)
+			r.Line = pos.Line
+			routes = append(routes, &r)
+		}
+	}
+
+	return routes
+}
+
+// attachRoutesToFunctions assigns each route in pa.Routes to the
+// FunctionInfo (free function or method) named by its Handler, when one
+// matches by name. Called once bindMethodsToStructs has finalized which
+// methods belong to which struct, so both free functions and methods
+// are available to match against.
+func attachRoutesToFunctions(pa *PackageAnalysis) {
+	byName := make(map[string]*FunctionInfo, len(pa.Functions))
+	for _, f := range pa.Functions {
+		byName[f.Name] = f
+	}
+	for _, s := range pa.Structs {
+		for _, m := range s.Methods {
+			if _, exists := byName[m.Name]; !exists {
+				byName[m.Name] = m
+			}
+		}
+	}
+
+	for _, r := range pa.Routes {
+		fi, ok := byName[r.Handler]
+		if !ok {
+			continue
+		}
+		fi.Routes = append(fi.Routes, r)
+		if isHTTPFramework(r.Framework) && r.Method != "" {
+			fi.HTTPMethod = r.Method
+			fi.HTTPPath = r.Path
+		}
+	}
+}
+
+// httpFrameworks are the RouteInfo.Framework values that represent an
+// HTTP-reachable entry point, as opposed to a message-queue consumer.
+var httpFrameworks = map[string]bool{
+	"net/http": true, "gin": true, "echo": true, "fiber": true,
+	"chi": true, "gorilla/mux": true, "grpc": true,
+}
+
+// mqFrameworks are the RouteInfo.Framework values for message-queue
+// consumers, used by inferClassType to recognize LISTENER packages.
+var mqFrameworks = map[string]bool{"kafka": true, "nats": true, "amqp": true}
+
+func isHTTPFramework(framework string) bool { return httpFrameworks[framework] }
@@ -10,6 +10,22 @@ package analysis
 type ProjectAnalysis struct {
 	Module   string             `json:"module"`
 	Packages []*PackageAnalysis `json:"packages"`
+
+	// CallGraph is the whole-program call graph built from SSA via
+	// buildCallGraph. Nil if the whole-project load (loadWholeProject)
+	// failed.
+	CallGraph *CallGraph `json:"callGraph,omitempty"`
+
+	// Implementers maps an interface's fully-qualified name
+	// ("pkgPath.IfaceName") to every struct (value or pointer receiver
+	// set) that satisfies it, computed via go/types.
+	Implementers map[string][]*StructInfo `json:"implementers,omitempty"`
+
+	// References maps a struct's, interface's, or function's
+	// fully-qualified name to every source location that refers to it:
+	// field/param/return type positions, method calls, and composite
+	// literals.
+	References map[string][]*Ref `json:"references,omitempty"`
 }
 
 // PackageAnalysis represents a single Go package with all its types,
@@ -45,6 +61,78 @@ type PackageAnalysis struct {
 
 	// ClassType is the inferred role of this package in the architecture.
 	ClassType string `json:"classType"`
+
+	// Diagnostics contains every finding reported by the registered
+	// go/analysis analyzers for this package, including those that are
+	// also attached to a FunctionInfo by position.
+	Diagnostics []*DiagnosticInfo `json:"diagnostics,omitempty"`
+
+	// Routes lists every route, RPC, or message-queue subscription
+	// registration found in this package by a FrameworkDetector.
+	Routes []*RouteInfo `json:"routes,omitempty"`
+
+	// BuildContexts lists every build-matrix context (see BuildContext)
+	// this package was successfully analyzed under, as "GOOS_GOARCH_cgo"
+	// keys. Structs/Interfaces/Functions above are the union across all
+	// of them; each carries its own Contexts subset.
+	BuildContexts []string `json:"buildContexts,omitempty"`
+
+	// Variants holds, for any build context whose file set diverged
+	// from the canonical view above, that context's own full
+	// extraction - e.g. what a windows-only build of this package
+	// actually looks like. Absent for platform-independent packages.
+	Variants map[string]*PackageVariant `json:"variants,omitempty"`
+
+	// Metrics aggregates the per-function Complexity and error-handling
+	// fields below across every function and method in the package.
+	Metrics *PackageMetrics `json:"metrics,omitempty"`
+}
+
+// PackageMetrics aggregates per-function complexity and error-handling
+// metrics (see FunctionInfo) across a whole package, so the domain
+// model exposed to the LLM can flag hotspots without walking every
+// function itself.
+type PackageMetrics struct {
+	// AvgComplexity is the mean McCabe cyclomatic complexity across all
+	// functions and methods in the package.
+	AvgComplexity float64 `json:"avgComplexity"`
+
+	// MaxComplexity is the highest complexity found in the package.
+	MaxComplexity int `json:"maxComplexity"`
+
+	// ErrorHandlingRatio is the fraction, among functions that return an
+	// error, which handle every error they return rather than
+	// discarding one via `_` within their own body. 1.0 means no
+	// function in the package ignores an error it could return; it is
+	// 0 when the package declares no error-returning functions.
+	ErrorHandlingRatio float64 `json:"errorHandlingRatio"`
+}
+
+// DiagnosticInfo is a single finding reported by a go/analysis.Analyzer
+// run against the project (vet, staticcheck, or a custom in-tree
+// analyzer registered via WithAnalyzers).
+type DiagnosticInfo struct {
+	// Analyzer is the name of the analyzer that produced this
+	// diagnostic (e.g., "printf", "unused", "nilness").
+	Analyzer string `json:"analyzer"`
+
+	// Category is the reporting analyzer's Doc text.
+	Category string `json:"category,omitempty"`
+
+	// Severity is one of "error", "warning", "info".
+	Severity string `json:"severity"`
+
+	// Message is the diagnostic text as reported by the analyzer.
+	Message string `json:"message"`
+
+	// File is the source file the diagnostic applies to (basename).
+	File string `json:"file"`
+
+	// Line is the 1-based line number of the diagnostic.
+	Line int `json:"line"`
+
+	// Col is the 1-based column number of the diagnostic.
+	Col int `json:"col"`
 }
 
 // StructInfo represents a Go struct type declaration.
@@ -67,9 +155,22 @@ type StructInfo struct {
 	// EmbeddedTypes lists the names of embedded (anonymous) types.
 	EmbeddedTypes []string `json:"embeddedTypes"`
 
-	// Implements lists interface names this struct implements
-	// (within the same module).
+	// Implements lists the fully-qualified names ("pkgPath.IfaceName")
+	// of every in-module interface this struct satisfies, computed via
+	// go/types.Implements against both value and pointer receiver sets
+	// (see buildIndices) - so embedded-method promotion is accounted
+	// for automatically, the same way the compiler sees it.
 	Implements []string `json:"implements"`
+
+	// UsedBy lists the fully-qualified names ("pkgPath.FuncName" or
+	// "pkgPath.Receiver.Method") of every in-module function or method
+	// that takes this struct as a parameter.
+	UsedBy []string `json:"usedBy,omitempty"`
+
+	// Contexts lists the build-matrix contexts (see BuildContext.Key)
+	// this struct was seen under. Unset matrix-independent packages
+	// carry the full BuildContexts set here too.
+	Contexts []string `json:"contexts,omitempty"`
 }
 
 // InterfaceInfo represents a Go interface type declaration.
@@ -88,12 +189,30 @@ type InterfaceInfo struct {
 
 	// EmbeddedInterfaces lists embedded interface names.
 	EmbeddedInterfaces []string `json:"embeddedInterfaces"`
+
+	// ImplementedBy lists the fully-qualified names
+	// ("pkgPath.StructName") of every in-module struct that satisfies
+	// this interface - the reverse of StructInfo.Implements.
+	ImplementedBy []string `json:"implementedBy,omitempty"`
+
+	// UsedBy lists the fully-qualified names ("pkgPath.FuncName" or
+	// "pkgPath.Receiver.Method") of every in-module function or method
+	// that takes this interface as a parameter.
+	UsedBy []string `json:"usedBy,omitempty"`
+
+	// Contexts lists the build-matrix contexts this interface was seen
+	// under (see BuildContext.Key).
+	Contexts []string `json:"contexts,omitempty"`
 }
 
 // MethodSignature represents a method signature in an interface.
 type MethodSignature struct {
 	Name   string       `json:"name"`
 	Params []*ParamInfo `json:"params"`
+
+	// Returns contains the return type names, rendered the same way as
+	// FunctionInfo.Returns.
+	Returns []string `json:"returns,omitempty"`
 }
 
 // FunctionInfo represents a function or method declaration.
@@ -107,6 +226,10 @@ type FunctionInfo struct {
 	// Line is the 1-based line number of the func declaration.
 	Line int `json:"line"`
 
+	// EndLine is the 1-based line number of the closing brace of the
+	// function body, used to attribute diagnostics by position.
+	EndLine int `json:"endLine,omitempty"`
+
 	// Receiver is the receiver type for methods (e.g., "*OrderService"),
 	// empty for package-level functions.
 	Receiver string `json:"receiver,omitempty"`
@@ -125,11 +248,74 @@ type FunctionInfo struct {
 	// empty otherwise.
 	HTTPPath string `json:"httpPath,omitempty"`
 
+	// Routes lists every registration a FrameworkDetector resolved back
+	// to this function or method as the handler.
+	Routes []*RouteInfo `json:"routes,omitempty"`
+
 	// HasPanic indicates the function body contains panic() calls.
 	HasPanic bool `json:"hasPanic,omitempty"`
 
+	// Complexity is the function's McCabe cyclomatic complexity: one
+	// plus the number of branching points (if, for, range, case,
+	// comm-clause, and short-circuit && / ||) in its body.
+	Complexity int `json:"complexity,omitempty"`
+
+	// LinesOfCode is the function's line count, from the line of its
+	// func keyword to its closing brace inclusive.
+	LinesOfCode int `json:"linesOfCode,omitempty"`
+
+	// ReturnsError indicates the function's last return value is error.
+	ReturnsError bool `json:"returnsError,omitempty"`
+
+	// IgnoredErrors lists every assignment in the function body that
+	// discards an error-typed value via `_`.
+	IgnoredErrors []Location `json:"ignoredErrors,omitempty"`
+
 	// Doc is the function's doc comment (first line only).
 	Doc string `json:"doc,omitempty"`
+
+	// Diagnostics contains go/analysis findings whose position falls
+	// within this function's body.
+	Diagnostics []*DiagnosticInfo `json:"diagnostics,omitempty"`
+
+	// TransitivelyPanics indicates this function calls (directly or
+	// transitively) another function known to panic, inferred via
+	// FactSet propagation. See facts.go.
+	TransitivelyPanics bool `json:"transitivelyPanics,omitempty"`
+
+	// HTTPReachable indicates this function is reachable from an HTTP
+	// handler, inferred via FactSet propagation.
+	HTTPReachable bool `json:"httpReachable,omitempty"`
+
+	// Callees lists every call site found in this function's body by
+	// the SSA-based call graph (see buildCallGraph), in-project and
+	// external alike - a denormalized view of CallGraph.Edges scoped to
+	// this function, for consumers that don't want to hold the whole
+	// ProjectAnalysis.CallGraph just to ask "what does this call".
+	Callees []*CallSite `json:"callees,omitempty"`
+
+	// Contexts lists the build-matrix contexts this function/method was
+	// seen under (see BuildContext.Key); e.g. a Windows-only helper
+	// carries just the "windows_..." keys.
+	Contexts []string `json:"contexts,omitempty"`
+
+	// fieldCalls records call sites of the form `field.Method(...)`
+	// found in the function body, keyed by the receiver field's name.
+	// Resolved to concrete fact keys once struct field types are known
+	// (see resolveFieldCalls), then discarded from JSON output since
+	// it is unexported.
+	fieldCalls []fieldCallRef
+
+	// calls holds the resolved fact keys ("pkgPath.Type.Method", see
+	// resolveFieldCalls) this function invokes, used by propagateFacts.
+	calls []string
+}
+
+// fieldCallRef is a not-yet-resolved call site of the form
+// `<field>.<method>(...)` discovered while walking a function body.
+type fieldCallRef struct {
+	Field  string
+	Method string
 }
 
 // ParamInfo represents a function parameter.
@@ -173,3 +359,12 @@ type FieldInfo struct {
 	// Tag is the struct field tag (e.g., `json:"name"`).
 	Tag string `json:"tag,omitempty"`
 }
+
+// Location is a single source position, used where a finding needs
+// nothing more than "where" (unlike Ref or DiagnosticInfo, which also
+// carry a kind/message).
+type Location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Col  int    `json:"col"`
+}
@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// describeType renders t the way FieldInfo/ParamInfo/FunctionInfo.Returns
+// want it: a fully-qualified type string (e.g.
+// "*github.com/user/repo/dto.Request"), plus the package path of its underlying named
+// type (empty for builtins and types from outside the module). Pointer,
+// slice, array, and variadic wrappers are unwrapped to find the
+// underlying named type.
+func describeType(t types.Type) (typeStr, pkgPath string, isPtr, isSlice bool) {
+	typeStr = types.TypeString(t, types.RelativeTo(nil))
+
+	u := t
+	for {
+		switch x := u.(type) {
+		case *types.Pointer:
+			isPtr = true
+			u = x.Elem()
+			continue
+		case *types.Slice:
+			isSlice = true
+			u = x.Elem()
+			continue
+		case *types.Array:
+			isSlice = true
+			u = x.Elem()
+			continue
+		}
+		break
+	}
+
+	if named, ok := u.(*types.Named); ok {
+		if obj := named.Obj(); obj != nil && obj.Pkg() != nil {
+			pkgPath = obj.Pkg().Path()
+		}
+	}
+
+	return typeStr, pkgPath, isPtr, isSlice
+}
+
+// typeStringFor renders expr's static type via info when available,
+// falling back to the plain AST-string rendering (exprToString) for
+// expressions info has no entry for (e.g. embedded fields in some
+// go/packages configurations).
+func typeStringFor(expr ast.Expr, info *types.Info) string {
+	if info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			return types.TypeString(t, types.RelativeTo(nil))
+		}
+	}
+	return exprToString(expr)
+}
+
+// packagePathFor resolves the fully-qualified package path of expr's
+// static type via info, or returns "" when info has no entry (callers
+// such as extractParamList then fall back to resolveTypePackage's
+// best-effort AST walk, which only yields the import alias).
+func packagePathFor(expr ast.Expr, info *types.Info) string {
+	if info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			_, pkgPath, _, _ := describeType(t)
+			return pkgPath
+		}
+	}
+	return ""
+}
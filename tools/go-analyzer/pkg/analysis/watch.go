@@ -0,0 +1,203 @@
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce batches rapid-fire filesystem events (editors often
+// write a file multiple times per save) before triggering a re-analysis.
+const watchDebounce = 300 * time.Millisecond
+
+// Event is a single incremental update emitted by Watch.
+type Event struct {
+	// Type is "packageUpdated" or "packageRemoved".
+	Type string `json:"type"`
+
+	// Path is the package's import path.
+	Path string `json:"path"`
+
+	// Package is the refreshed analysis, present only for
+	// "packageUpdated" events.
+	Package *PackageAnalysis `json:"package,omitempty"`
+}
+
+// Watch keeps the analyzer running against projectRoot. After each
+// debounced batch of .go file changes it re-runs discovery over the
+// whole project, and invokes onEvent with an Event for every package
+// whose analysis changed or that disappeared. It reuses the same cache
+// and concurrent dependency-graph loader as Analyze, so packages whose
+// files and dependencies did not change are served from warm cache
+// entries when the cache is enabled. Blocks until ctx is cancelled.
+func (a *Analyzer) Watch(ctx context.Context, onEvent func(Event)) error {
+	modulePath, err := a.readModulePath()
+	if err != nil {
+		return fmt.Errorf("reading go.mod: %w", err)
+	}
+	a.modulePath = modulePath
+	a.gqlSchema = a.loadGQLGenSchema()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("starting fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirs(watcher, a.projectRoot); err != nil {
+		return fmt.Errorf("watching %s: %w", a.projectRoot, err)
+	}
+
+	// current tracks every package we know about, by import path, so
+	// later passes can detect removals and recompute cross-package
+	// facts over the whole known set.
+	current := make(map[string]*PackageAnalysis)
+
+	// refresh re-walks and re-analyzes every package in the project -
+	// it has no per-directory entry point to narrow to the dirs a
+	// fsnotify batch actually touched, so it relies entirely on
+	// discoverPackages' on-disk cache (see cacheintegration.go) to make
+	// re-analyzing unaffected packages cheap.
+	refresh := func() {
+		packages, err := a.discoverPackages()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: re-analysis failed: %v\n", err)
+			return
+		}
+
+		seen := make(map[string]bool, len(packages))
+		for _, pkg := range packages {
+			seen[pkg.Path] = true
+		}
+
+		a.propagateFacts(packages)
+
+		for _, pkg := range packages {
+			prev, existed := current[pkg.Path]
+			changed := !existed || !samePackageAnalysis(prev, pkg)
+			current[pkg.Path] = pkg
+			if changed {
+				onEvent(Event{Type: "packageUpdated", Path: pkg.Path, Package: pkg})
+			}
+		}
+
+		for path := range current {
+			if !seen[path] {
+				delete(current, path)
+				onEvent(Event{Type: "packageRemoved", Path: path})
+			}
+		}
+	}
+
+	// Initial full analysis.
+	refresh()
+
+	pending := make(map[string]bool)
+	var timer *time.Timer
+	timerC := func() <-chan time.Time {
+		if timer == nil {
+			return nil
+		}
+		return timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !strings.HasSuffix(ev.Name, ".go") {
+				continue
+			}
+			pending[filepath.Dir(ev.Name)] = true
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+			} else {
+				timer.Reset(watchDebounce)
+			}
+
+		case <-timerC():
+			timer = nil
+			pending = make(map[string]bool)
+			refresh()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "WARN: fsnotify error: %v\n", err)
+		}
+	}
+}
+
+// addWatchDirs recursively registers every non-excluded directory under
+// root with the watcher.
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if isExcludedDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// samePackageAnalysis reports whether two analyses of the same package
+// path are identical, compared by their JSON serialization - the same
+// representation every consumer of this tool actually receives, so
+// "did anything change" means exactly what "did the emitted JSON
+// change" means. This catches edits that leave declaration counts
+// untouched (a changed function body, branch, route string, or added
+// panic), which sameFileSet's shallower file-list-and-count comparison
+// (used by buildmatrix.go for a narrower "does this build context's
+// file set diverge from canonical" question) would miss entirely.
+// discoverPackages hands back a new (and so always !=) pointer, freshly
+// analyzed or freshly unmarshaled from the cache, on every call
+// regardless of whether content changed, which is why this can't just
+// compare a and b directly.
+func samePackageAnalysis(a, b *PackageAnalysis) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// sameFileSet reports whether two analyses of the same package path
+// have identical file lists plus matching declaration counts, used by
+// mergeVariant to decide whether a build context's extraction actually
+// diverges from the canonical one.
+func sameFileSet(a, b *PackageAnalysis) bool {
+	if len(a.Files) != len(b.Files) {
+		return false
+	}
+	seen := make(map[string]bool, len(a.Files))
+	for _, f := range a.Files {
+		seen[f] = true
+	}
+	for _, f := range b.Files {
+		if !seen[f] {
+			return false
+		}
+	}
+	return len(a.Structs) == len(b.Structs) &&
+		len(a.Interfaces) == len(b.Interfaces) &&
+		len(a.Functions) == len(b.Functions)
+}
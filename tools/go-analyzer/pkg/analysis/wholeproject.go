@@ -0,0 +1,32 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loadWholeProject loads every package in the module in one
+// packages.Load call (pattern "./..."), fully type-checked. Unlike the
+// per-directory concurrent loader used for structural extraction, this
+// gives a single consistent type-checked universe, which both the SSA
+// call graph (buildCallGraph) and the cross-package reference/
+// implementer indices (buildIndices) require.
+func (a *Analyzer) loadWholeProject() ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packagesLoadMode,
+		Dir:  a.projectRoot,
+		Fset: a.fset,
+	}
+
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("loading whole project: %w", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		fmt.Fprintf(os.Stderr, "WARN: whole-project analysis built from a partially type-checked program\n")
+	}
+
+	return pkgs, nil
+}
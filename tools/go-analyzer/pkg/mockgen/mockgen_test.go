@@ -0,0 +1,99 @@
+package mockgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fanki/go-analyzer/pkg/analysis"
+)
+
+// twoReadersProject builds a ProjectAnalysis with two unrelated packages
+// that each declare their own "Reader" interface, both embedded into a
+// target interface in a third package - the scenario where a bare-name
+// visiting guard would mistake the second Reader for a cycle on the
+// first and silently drop its methods.
+func twoReadersProject() *analysis.ProjectAnalysis {
+	readerA := &analysis.InterfaceInfo{
+		Name: "Reader",
+		Methods: []*analysis.MethodSignature{
+			{Name: "ReadA", Returns: []string{"error"}},
+		},
+	}
+	readerB := &analysis.InterfaceInfo{
+		Name: "Reader",
+		Methods: []*analysis.MethodSignature{
+			{Name: "ReadB", Returns: []string{"error"}},
+		},
+	}
+	target := &analysis.InterfaceInfo{
+		Name:               "Combined",
+		EmbeddedInterfaces: []string{"pkg/a.Reader", "pkg/b.Reader"},
+	}
+
+	return &analysis.ProjectAnalysis{
+		Module: "example.com/app",
+		Packages: []*analysis.PackageAnalysis{
+			{Path: "pkg/a", Interfaces: []*analysis.InterfaceInfo{readerA}},
+			{Path: "pkg/b", Interfaces: []*analysis.InterfaceInfo{readerB}},
+			{Path: "pkg/target", Interfaces: []*analysis.InterfaceInfo{target}},
+		},
+	}
+}
+
+// TestFlattenMethods_SameNameEmbeddedInterfaces guards against
+// flattenMethods' visiting guard colliding on bare interface names:
+// embedding two different packages' same-named "Reader" interfaces must
+// contribute both ReadA and ReadB, not just the first one found.
+func TestFlattenMethods_SameNameEmbeddedInterfaces(t *testing.T) {
+	proj := twoReadersProject()
+
+	byQName := make(map[string]*analysis.InterfaceInfo)
+	for _, pa := range proj.Packages {
+		for _, iface := range pa.Interfaces {
+			byQName[pa.Path+"."+iface.Name] = iface
+		}
+	}
+
+	target := byQName["pkg/target.Combined"]
+	methods := flattenMethods("pkg/target.Combined", target, byQName, map[string]bool{})
+
+	var names []string
+	for _, m := range methods {
+		names = append(names, m.Name)
+	}
+
+	if len(methods) != 2 {
+		t.Fatalf("expected 2 methods from both embedded Readers, got %d: %v", len(methods), names)
+	}
+	for _, want := range []string{"ReadA", "ReadB"} {
+		found := false
+		for _, n := range names {
+			if n == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected method %s to survive flattening, got %v", want, names)
+		}
+	}
+}
+
+// TestGenerate_SameNameEmbeddedInterfaces is the same scenario end to
+// end through Generate, asserting the rendered mock actually declares
+// both methods.
+func TestGenerate_SameNameEmbeddedInterfaces(t *testing.T) {
+	proj := twoReadersProject()
+
+	files, err := Generate(proj, "pkg/target", "mocks", ModeRecorder)
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 generated file, got %d", len(files))
+	}
+
+	src := files[0].Source
+	if !strings.Contains(src, "ReadA") || !strings.Contains(src, "ReadB") {
+		t.Errorf("expected generated mock to declare both ReadA and ReadB, got:\n%s", src)
+	}
+}
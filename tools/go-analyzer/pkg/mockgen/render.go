@@ -0,0 +1,57 @@
+package mockgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fanki/go-analyzer/pkg/analysis"
+)
+
+// paramNames renders the parameter names method expects, synthesizing
+// "argN" for any interface method parameter left unnamed in source (a
+// common style: "Find(string) (*Order, error)").
+func paramNames(m *analysis.MethodSignature) []string {
+	names := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		if p.Name != "" {
+			names[i] = p.Name
+		} else {
+			names[i] = fmt.Sprintf("arg%d", i)
+		}
+	}
+	return names
+}
+
+// paramTypeDecl renders p's type the way a func signature needs it:
+// "...T" for a variadic parameter, p.Type unchanged otherwise.
+func paramTypeDecl(p *analysis.ParamInfo) string {
+	if !p.IsVariadic {
+		return p.Type
+	}
+	t := strings.TrimPrefix(p.Type, "...")
+	t = strings.TrimPrefix(t, "[]")
+	return "..." + t
+}
+
+// paramList renders m's parameter list as Go source: "name1 Type1, name2 Type2".
+func paramList(m *analysis.MethodSignature) string {
+	names := paramNames(m)
+	parts := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		parts[i] = names[i] + " " + paramTypeDecl(p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// resultList renders m's return list as Go source: "" (none), "T" (one),
+// or "(T1, T2)" (more than one).
+func resultList(m *analysis.MethodSignature) string {
+	switch len(m.Returns) {
+	case 0:
+		return ""
+	case 1:
+		return m.Returns[0]
+	default:
+		return "(" + strings.Join(m.Returns, ", ") + ")"
+	}
+}
@@ -0,0 +1,78 @@
+// Package openapi derives an OpenAPI 3.1 document from an
+// analysis.ProjectAnalysis: every FunctionInfo carrying an HTTPMethod
+// and HTTPPath (as detected by the analysis package's FrameworkDetectors)
+// becomes one path/operation, and request/response bodies are
+// synthesized from the handler's ParamInfo/FieldInfo trees.
+package openapi
+
+// Document is the root of a generated OpenAPI 3.1 document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info is the OpenAPI document's required "info" object.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps an HTTP verb (lowercase: "get", "post", ...) to the
+// Operation registered for it on one path.
+type PathItem map[string]Operation
+
+// Operation is a single path+verb's OpenAPI operation object.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes one path or query parameter.
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"` // "path" or "query"
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+// RequestBody is an operation's request body, keyed by media type.
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+// Response is one response status code's entry in an operation's
+// Responses map.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType pairs a media type (currently always "application/json")
+// with the Schema describing its body.
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// Schema is a JSON Schema fragment, covering the subset OpenAPI 3.1
+// (which adopted JSON Schema 2020-12 directly) needs for the handler
+// shapes this package can infer: primitives, arrays, $ref to a
+// Components.Schemas entry, and plain objects with properties.
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+}
+
+// Components holds every named schema referenced by $ref elsewhere in
+// the document, keyed by struct name.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
@@ -0,0 +1,327 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/fanki/go-analyzer/pkg/analysis"
+)
+
+// validVerbs are the HTTPMethod values Generate turns into an OpenAPI
+// operation. Anything else (the "ANY" routerVerbs produce, or the
+// gorilla/mux "" left by an unparsed .Methods() chain, or "GRAPHQL")
+// has no single corresponding OpenAPI verb and is skipped.
+var validVerbs = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true,
+	"DELETE": true, "HEAD": true, "OPTIONS": true,
+}
+
+// frameworkParamTypes are handler parameter types that carry the
+// request/context plumbing itself rather than request data, so Generate
+// never mistakes them for a JSON request body. Each is listed both in
+// the fully-qualified form ParamInfo.Type carries when type info is
+// available and in the short form it falls back to without it.
+var frameworkParamTypes = map[string]bool{
+	"context.Context": true, "gin.Context": true, "echo.Context": true,
+	"fiber.Ctx": true, "http.ResponseWriter": true, "http.Request": true,
+	"github.com/gin-gonic/gin.Context": true, "github.com/labstack/echo/v4.Context": true,
+	"github.com/gofiber/fiber/v2.Ctx": true, "net/http.ResponseWriter": true, "net/http.Request": true,
+}
+
+// Generate walks every package in proj and emits one OpenAPI path+
+// operation per handler FunctionInfo (free function or method) carrying
+// an HTTPMethod/HTTPPath, as detected by analysis.FrameworkDetector.
+// title and version populate the document's required Info object.
+func Generate(proj *analysis.ProjectAnalysis, title, version string) *Document {
+	structIndex := make(map[string]*analysis.StructInfo)
+	for _, pa := range proj.Packages {
+		for _, s := range pa.Structs {
+			structIndex[pa.Path+"."+s.Name] = s
+		}
+	}
+
+	doc := &Document{
+		OpenAPI:    "3.1.0",
+		Info:       Info{Title: title, Version: version},
+		Paths:      make(map[string]PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+
+	for _, pa := range proj.Packages {
+		for _, f := range pa.Functions {
+			addOperation(doc, f, structIndex)
+		}
+		for _, s := range pa.Structs {
+			for _, m := range s.Methods {
+				addOperation(doc, m, structIndex)
+			}
+		}
+	}
+
+	return doc
+}
+
+// addOperation adds one path/operation for f to doc, a no-op if f isn't
+// a handler for a verb Generate understands or its HTTPPath is empty.
+func addOperation(doc *Document, f *analysis.FunctionInfo, structIndex map[string]*analysis.StructInfo) {
+	verb := strings.ToUpper(f.HTTPMethod)
+	if !validVerbs[verb] {
+		return
+	}
+
+	path, pathParams := normalizePath(f.HTTPPath)
+	if path == "" {
+		return
+	}
+
+	op := Operation{
+		OperationID: operationID(f),
+		Summary:     f.Doc,
+		Responses:   map[string]Response{"200": {Description: "OK"}},
+	}
+
+	paramByName := make(map[string]*analysis.ParamInfo, len(f.Params))
+	for _, p := range f.Params {
+		paramByName[strings.ToLower(p.Name)] = p
+	}
+
+	for _, name := range pathParams {
+		schema := Schema{Type: "string"}
+		if p, ok := paramByName[strings.ToLower(name)]; ok {
+			if s := primitiveSchema(p.Type); s != nil {
+				schema = *s
+			}
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name: name, In: "path", Required: true, Schema: schema,
+		})
+	}
+
+	if verb == "POST" || verb == "PUT" || verb == "PATCH" {
+		if body := requestBodySchema(f, pathParams, structIndex, doc.Components.Schemas); body != nil {
+			op.RequestBody = &RequestBody{Content: map[string]MediaType{
+				"application/json": {Schema: *body},
+			}}
+		}
+	}
+
+	if doc.Paths[path] == nil {
+		doc.Paths[path] = PathItem{}
+	}
+	doc.Paths[path][strings.ToLower(verb)] = op
+}
+
+// requestBodySchema picks the first handler parameter that is neither a
+// path parameter nor a known framework type (context, *gin.Context, the
+// raw http.Request, ...) and renders its type as a JSON Schema, or nil
+// if no such parameter exists.
+func requestBodySchema(
+	f *analysis.FunctionInfo,
+	pathParams []string,
+	structIndex map[string]*analysis.StructInfo,
+	schemas map[string]*Schema,
+) *Schema {
+	isPathParam := make(map[string]bool, len(pathParams))
+	for _, name := range pathParams {
+		isPathParam[strings.ToLower(name)] = true
+	}
+
+	for _, p := range f.Params {
+		if isPathParam[strings.ToLower(p.Name)] {
+			continue
+		}
+		if frameworkParamTypes[strings.TrimPrefix(strings.TrimPrefix(p.Type, "*"), "[]")] {
+			continue
+		}
+		return schemaForType(p.Type, structIndex, schemas, map[string]bool{})
+	}
+	return nil
+}
+
+// operationID renders f as "Receiver.Name" for a method, or bare "Name"
+// for a free function.
+func operationID(f *analysis.FunctionInfo) string {
+	if f.Receiver == "" {
+		return f.Name
+	}
+	return strings.TrimPrefix(f.Receiver, "*") + "." + f.Name
+}
+
+// normalizePath rewrites path into OpenAPI's "{param}" segment syntax
+// and returns the parameter names found, recognizing both that syntax
+// (net/http 1.22+, gorilla/mux, chi) and gin/echo's ":param" segments.
+func normalizePath(path string) (string, []string) {
+	if path == "" {
+		return "", nil
+	}
+
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			params = append(params, strings.Trim(seg, "{}"))
+		case strings.HasPrefix(seg, ":") && len(seg) > 1:
+			name := seg[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, name)
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// primitiveSchema maps a Go type string (as rendered into
+// ParamInfo.Type/FieldInfo.Type) to a JSON Schema primitive, or nil if
+// it isn't one of Go's basic types (a project type that instead needs a
+// $ref - see schemaForType).
+func primitiveSchema(goType string) *Schema {
+	t := strings.TrimPrefix(goType, "*")
+	array := strings.HasPrefix(t, "[]")
+	if array {
+		t = strings.TrimPrefix(strings.TrimPrefix(t, "[]"), "*")
+	}
+
+	var s *Schema
+	switch t {
+	case "string":
+		s = &Schema{Type: "string"}
+	case "bool":
+		s = &Schema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		s = &Schema{Type: "integer"}
+	case "float32", "float64":
+		s = &Schema{Type: "number"}
+	case "time.Time":
+		s = &Schema{Type: "string", Format: "date-time"}
+	default:
+		return nil
+	}
+
+	if array {
+		return &Schema{Type: "array", Items: s}
+	}
+	return s
+}
+
+// schemaForType renders goType as a Schema: a primitive directly, or a
+// $ref into schemas (building that component on first use, via
+// ensureComponentSchema) when it names a struct this project declares,
+// or a bare "object" schema as a last resort (an external/unknown type
+// Generate has no field information for). structIndex is keyed by
+// "pkgPath.Name" (see Generate) so two packages' same-named structs
+// (e.g. two "Order" DTOs) resolve to the right one.
+func schemaForType(
+	goType string,
+	structIndex map[string]*analysis.StructInfo,
+	schemas map[string]*Schema,
+	visiting map[string]bool,
+) *Schema {
+	if s := primitiveSchema(goType); s != nil {
+		return s
+	}
+
+	stripped := strings.TrimPrefix(goType, "*")
+	array := strings.HasPrefix(stripped, "[]")
+	if array {
+		stripped = strings.TrimPrefix(strings.TrimPrefix(stripped, "[]"), "*")
+	}
+
+	si, ok := structIndex[stripped]
+	if !ok {
+		if array {
+			return &Schema{Type: "array", Items: &Schema{Type: "object"}}
+		}
+		return &Schema{Type: "object"}
+	}
+
+	schemaName := componentSchemaName(stripped)
+	ensureComponentSchema(schemaName, si, structIndex, schemas, visiting)
+	ref := &Schema{Ref: "#/components/schemas/" + schemaName}
+	if array {
+		return &Schema{Type: "array", Items: ref}
+	}
+	return ref
+}
+
+// componentSchemaName turns a structIndex key ("pkgPath.Name") into a
+// name safe to use as both an OpenAPI components/schemas map key and a
+// "#/components/schemas/<name>" JSON Pointer reference, which cannot
+// itself contain an unescaped "/" - so the package path's slashes are
+// folded into underscores rather than dropped, keeping two different
+// packages' same-named structs from colliding here the same way
+// structIndex's bare-name keying used to.
+func componentSchemaName(qualifiedName string) string {
+	return strings.ReplaceAll(qualifiedName, "/", "_")
+}
+
+// ensureComponentSchema builds schemas[schemaName] the first time it's
+// referenced, walking si's exported fields (respecting their json tag's
+// name, "-", and omitempty) and following $ref links for any field
+// whose type is itself a project struct. The placeholder entry is
+// written before recursing into fields so a self- or mutually-
+// referential struct doesn't loop forever - by the time a cyclical
+// $ref is followed, the name is already reserved in schemas.
+func ensureComponentSchema(
+	schemaName string,
+	si *analysis.StructInfo,
+	structIndex map[string]*analysis.StructInfo,
+	schemas map[string]*Schema,
+	visiting map[string]bool,
+) {
+	if _, done := schemas[schemaName]; done {
+		return
+	}
+	if visiting[schemaName] {
+		return
+	}
+	visiting[schemaName] = true
+	defer delete(visiting, schemaName)
+
+	schemas[schemaName] = &Schema{Type: "object"}
+
+	properties := make(map[string]*Schema, len(si.Fields))
+	var required []string
+	for _, f := range si.Fields {
+		if !f.IsExported || f.Name == "" {
+			continue
+		}
+		jsonName, omit := jsonFieldName(f)
+		if jsonName == "" {
+			continue
+		}
+
+		properties[jsonName] = schemaForType(f.Type, structIndex, schemas, visiting)
+		if !omit && !strings.HasPrefix(f.Type, "*") {
+			required = append(required, jsonName)
+		}
+	}
+
+	schemas[schemaName] = &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// jsonFieldName resolves f's JSON property name the way encoding/json
+// would: the tag's name if present, f.Name otherwise, or ("", false)
+// when the tag opts the field out entirely (`json:"-"`). omit reports
+// whether the tag carries "omitempty".
+func jsonFieldName(f *analysis.FieldInfo) (name string, omit bool) {
+	tag := reflect.StructTag(strings.Trim(f.Tag, "`")).Get("json")
+	if tag == "" {
+		return f.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omit = true
+		}
+	}
+
+	if parts[0] == "-" && len(parts) == 1 {
+		return "", omit
+	}
+	if parts[0] == "" {
+		return f.Name, omit
+	}
+	return parts[0], omit
+}
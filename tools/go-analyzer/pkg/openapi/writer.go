@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteJSON writes doc to w as indented JSON.
+func WriteJSON(w io.Writer, doc *Document) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding OpenAPI document: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteYAML writes doc to w as YAML. There's no YAML library in this
+// module, so this round-trips doc through encoding/json into a generic
+// map[string]any/[]any tree (doc's own json tags already define every
+// key name) and walks that with a small block-style emitter - enough
+// for the maps, slices, strings, numbers, and bools an OpenAPI document
+// is made of.
+func WriteYAML(w io.Writer, doc *Document) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("encoding OpenAPI document: %w", err)
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("decoding intermediate representation: %w", err)
+	}
+
+	var b strings.Builder
+	writeYAMLValue(&b, v, 0, false)
+	_, err = w.Write([]byte(b.String()))
+	return err
+}
+
+// writeYAMLValue renders v at the given indent depth. inline is true
+// when the caller already emitted "key:" or "- " on the current line
+// and a scalar can simply follow it; non-empty composite values always
+// start their own indented block on the next line regardless of inline,
+// while an empty map or list is written in place as "{}" or "[]".
+func writeYAMLValue(b *strings.Builder, v any, indent int, inline bool) {
+	pad := strings.Repeat("  ", indent)
+
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		if inline {
+			b.WriteByte('\n')
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.WriteString(pad)
+			b.WriteString(yamlKey(k))
+			b.WriteByte(':')
+			writeYAMLChild(b, val[k], indent)
+		}
+
+	case []any:
+		if len(val) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		if inline {
+			b.WriteByte('\n')
+		}
+		for _, item := range val {
+			b.WriteString(pad)
+			b.WriteString("- ")
+			writeYAMLValue(b, item, indent+1, true)
+		}
+
+	default:
+		b.WriteByte(' ')
+		b.WriteString(yamlScalar(val))
+		b.WriteByte('\n')
+	}
+}
+
+// writeYAMLChild renders a map value or list item that follows a "key:"
+// on the current line: scalars stay on that line, composites drop to
+// their own indented block.
+func writeYAMLChild(b *strings.Builder, v any, indent int) {
+	switch v.(type) {
+	case map[string]any, []any:
+		writeYAMLValue(b, v, indent+1, true)
+	default:
+		writeYAMLValue(b, v, indent, true)
+	}
+}
+
+// yamlKey quotes k only when it is empty or contains '$', ':' or '#'
+// (e.g. "$ref"); every other key, including paths and status codes, is
+// written as-is.
+func yamlKey(k string) string {
+	if k == "" {
+		return `""`
+	}
+	for _, r := range k {
+		if r == '$' || r == ':' || r == '#' {
+			return strconv.Quote(k)
+		}
+	}
+	return k
+}
+
+// yamlScalar renders a decoded JSON scalar (string, float64, bool, or
+// nil) as a YAML scalar.
+func yamlScalar(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if s {
+			return "true"
+		}
+		return "false"
+	case float64:
+		return strconv.FormatFloat(s, 'g', -1, 64)
+	case string:
+		if s == "" || needsYAMLQuoting(s) {
+			return strconv.Quote(s)
+		}
+		return s
+	default:
+		return fmt.Sprintf("%v", s)
+	}
+}
+
+// needsYAMLQuoting reports whether s needs quoting to avoid being
+// misread as a different YAML type (a bool, null, number, or a string
+// starting with a character that has block-syntax meaning).
+func needsYAMLQuoting(s string) bool {
+	switch s {
+	case "true", "false", "null", "~", "yes", "no":
+		return true
+	}
+	switch s[0] {
+	case '-', '?', ':', '{', '}', '[', ']', ',', '&', '*', '#', '|', '>', '!', '%', '@', '`', '"', '\'', ' ':
+		return true
+	}
+	if strings.ContainsAny(s, "\n\t") {
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	return false
+}